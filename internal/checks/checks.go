@@ -0,0 +1,561 @@
+package checks
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Percona-Lab/mysql_random_data_load/internal/generators"
+	"github.com/Percona-Lab/mysql_random_data_load/internal/getters"
+	"github.com/Percona-Lab/mysql_random_data_load/tableparser"
+	log "github.com/sirupsen/logrus"
+)
+
+// Options holds the options used to build the getters satisfying the CHECK constraints
+type Options struct {
+	Seed  int64            // seed for the random values generators
+	Specs generators.Specs // user defined generators. Fields having one keep their getters
+	// TemporalRange returns the range of values for date, datetime, timestamp and year fields
+	TemporalRange func(dataType string) getters.TemporalRange
+}
+
+// Apply makes the getters of the fields having conditions in CHECK constraints generate values satisfying
+// them. fields are the fields having a getter in values, in the same order. Constraints that cannot be
+// enforced are reported before loading the table; rows violating them are discarded by INSERT IGNORE. It
+// returns an error if the conditions on a field cannot be satisfied.
+func Apply(table *tableparser.Table, fields []tableparser.Field, values []getters.Getter, opts Options) error {
+	if len(fields) != len(values) {
+		return fmt.Errorf("the table %s has %d fields to insert but %d getters", table.Name, len(fields), len(values))
+	}
+	if len(table.Checks) == 0 {
+		return nil
+	}
+	tableFields := make(map[string]tableparser.Field)
+	for _, field := range table.Fields {
+		tableFields[field.ColumnName] = field
+	}
+	positions := make(map[string]int)
+	for i, field := range fields {
+		positions[field.ColumnName] = i
+	}
+
+	conditions := make(map[string][]tableparser.CheckCondition)
+	comparisons := []tableparser.CheckCondition{}
+	for _, check := range table.Checks {
+		if !check.Enforced {
+			continue
+		}
+		conds, err := tableparser.ParseCheck(check.Clause)
+		if err != nil {
+			log.Warnf("The CHECK constraint %s cannot be enforced: %s. Rows violating it will be discarded",
+				check.Name, err)
+			continue
+		}
+		for _, cond := range conds {
+			if err := validateCondition(cond, tableFields, positions); err != nil {
+				log.Warnf("The CHECK constraint %s cannot be fully enforced: %s. Rows violating it will be discarded",
+					check.Name, err)
+				continue
+			}
+			if cond.RefColumn != "" {
+				comparisons = append(comparisons, cond)
+				continue
+			}
+			conditions[cond.Column] = append(conditions[cond.Column], cond)
+		}
+	}
+
+	checked := make(map[string]*getters.Checked)
+	for _, field := range fields {
+		conds, ok := conditions[field.ColumnName]
+		if !ok {
+			continue
+		}
+		if _, ok := opts.Specs.Get(field.TableSchema, field.TableName, field.ColumnName); ok {
+			log.Warnf("The field %s has CHECK constraints but it uses the generator from the generators file",
+				field.ColumnName)
+			continue
+		}
+		pos := positions[field.ColumnName]
+		g, err := makeGetter(field, conds, values[pos], opts)
+		if err != nil {
+			return fmt.Errorf("the CHECK constraints on field %s cannot be satisfied: %s", field.ColumnName, err)
+		}
+		values[pos] = g
+		checked[field.ColumnName] = g
+	}
+
+	// The field generated last in each row is compared with the last value of the other field
+	for _, cond := range comparisons {
+		if positions[cond.Column] < positions[cond.RefColumn] {
+			cond = cond.Reverse()
+		}
+		for _, name := range []string{cond.Column, cond.RefColumn} {
+			if checked[name] == nil {
+				checked[name] = getters.NewChecked(values[positions[name]], tableFields[name].DataType == "date")
+				values[positions[name]] = checked[name]
+			}
+		}
+		ref, op := checked[cond.RefColumn], cond.Op
+		checked[cond.Column].AddCondition(func(v interface{}) bool {
+			last := ref.Last()
+			return v == nil || last == nil || getters.Compare(op, v, last)
+		})
+	}
+	return nil
+}
+
+// validateCondition returns an error if the condition cannot be enforced by the getters
+func validateCondition(cond tableparser.CheckCondition, fields map[string]tableparser.Field,
+	positions map[string]int) error {
+	for _, name := range []string{cond.Column, cond.RefColumn} {
+		if _, ok := fields[name]; name != "" && !ok {
+			return fmt.Errorf("unknown field %s", name)
+		}
+		if _, ok := positions[name]; name != "" && !ok {
+			return fmt.Errorf("the field %s is not generated", name)
+		}
+	}
+	field := fields[cond.Column]
+	if !isCheckedType(field.DataType) {
+		return fmt.Errorf("conditions on %s fields are not supported", field.DataType)
+	}
+
+	switch {
+	case cond.RefColumn != "":
+		if cond.Op == "=" {
+			return fmt.Errorf("equality between fields is not supported")
+		}
+		if !isOrderedType(field.DataType) || !isOrderedType(fields[cond.RefColumn].DataType) {
+			return fmt.Errorf("comparisons between %s and %s fields are not supported", field.DataType,
+				fields[cond.RefColumn].DataType)
+		}
+		return nil
+	case cond.Function != "":
+		if !getters.IsStringType(field.DataType) {
+			return fmt.Errorf("length conditions on %s fields are not supported", field.DataType)
+		}
+		if cond.Op == "in" || cond.Op == "not in" {
+			return fmt.Errorf("IN conditions on lengths are not supported")
+		}
+		for _, v := range cond.Values {
+			if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+				return fmt.Errorf("invalid length %q", v)
+			}
+		}
+		return nil
+	case cond.Op == "<" || cond.Op == "<=" || cond.Op == ">" || cond.Op == ">=" || cond.Op == "between":
+		if !isOrderedType(field.DataType) {
+			return fmt.Errorf("range conditions on %s fields are not supported", field.DataType)
+		}
+		for _, v := range cond.Values {
+			if _, err := parseBound(field, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bound is a lower or upper bound for the values of a field
+type bound struct {
+	value     string
+	inclusive bool
+}
+
+// makeGetter returns a getter generating values satisfying the conditions on the field. Values are generated
+// in the range defined by the conditions and then filtered by all the conditions.
+func makeGetter(field tableparser.Field, conds []tableparser.CheckCondition, current getters.Getter,
+	opts Options) (*getters.Checked, error) {
+	rnd := getters.NewColumnRand(opts.Seed, field.TableSchema, field.TableName, field.ColumnName)
+	var isNull, notNull bool
+	var in []string
+	var lower, upper []bound
+	minLength, maxLength := int64(0), field.CharacterMaximumLength.Int64
+	conditions := []getters.Condition{}
+
+	for _, cond := range conds {
+		conditions = append(conditions, condition(cond))
+		if cond.Function != "" {
+			min, max := lengthBounds(cond)
+			if min > minLength {
+				minLength = min
+			}
+			if max >= 0 && (max < maxLength || maxLength <= 0) {
+				maxLength = max
+			}
+			continue
+		}
+		switch cond.Op {
+		case "is null":
+			isNull = true
+		case "is not null":
+			notNull = true
+		case "=", "in":
+			if in == nil {
+				in = cond.Values
+			}
+		case ">", ">=":
+			lower = append(lower, bound{cond.Values[0], cond.Op == ">="})
+		case "<", "<=":
+			upper = append(upper, bound{cond.Values[0], cond.Op == "<="})
+		case "between":
+			lower = append(lower, bound{cond.Values[0], true})
+			upper = append(upper, bound{cond.Values[1], true})
+		}
+	}
+	allowNull := field.IsNullable && !notNull
+
+	g := current
+	switch {
+	case isNull:
+		if !field.IsNullable || notNull {
+			return nil, fmt.Errorf("the field must be NULL but it doesn't accept NULLs")
+		}
+		g = getters.NewNullRatio(current, 1, rnd)
+	case in != nil:
+		// Values in the list also have to satisfy the other conditions
+		values := []string{}
+		for _, v := range in {
+			accepted := field.DataType != "enum"
+			for _, member := range field.SetEnumVals {
+				accepted = accepted || strings.EqualFold(member, v)
+			}
+			for _, c := range conditions {
+				accepted = accepted && c(v)
+			}
+			if accepted {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("no value in %s satisfies all the conditions", strings.Join(in, ", "))
+		}
+		g = getters.NewRandomEnum(values, allowNull, rnd)
+	case field.Constraint != nil:
+		// Foreign key samples are only filtered
+	case len(lower) > 0 || len(upper) > 0:
+		var err error
+		if g, err = makeRangeGetter(field, lower, upper, allowNull, opts, rnd); err != nil {
+			return nil, err
+		}
+	case minLength > 0 || maxLength < field.CharacterMaximumLength.Int64:
+		if minLength > maxLength {
+			return nil, fmt.Errorf("the min length %d is greater than the max length %d", minLength, maxLength)
+		}
+		switch field.DataType {
+		case "char", "varchar", "tinytext", "text", "mediumtext", "longtext":
+			g = getters.NewRandomStringRange(field.ColumnName, minLength, maxLength, allowNull, rnd)
+		}
+	}
+	return getters.NewChecked(g, field.DataType == "date", conditions...), nil
+}
+
+// makeRangeGetter returns a getter for numeric and temporal values between the lower and upper bounds
+func makeRangeGetter(field tableparser.Field, lower, upper []bound, allowNull bool, opts Options,
+	rnd *rand.Rand) (getters.Getter, error) {
+	if isTemporalType(field.DataType) {
+		legal := getters.DefaultTemporalRange(field.DataType)
+		unit := time.Second
+		if field.DataType == "date" {
+			unit = 24 * time.Hour
+		}
+		min, max := legal.Min, legal.Max
+		for _, b := range lower {
+			t, _ := getters.ParseDateTime(b.value)
+			if !b.inclusive {
+				t = t.Add(unit)
+			}
+			if t.After(min) {
+				min = t
+			}
+		}
+		for _, b := range upper {
+			t, _ := getters.ParseDateTime(b.value)
+			if !b.inclusive {
+				t = t.Add(-unit)
+			}
+			if t.Before(max) {
+				max = t
+			}
+		}
+		// Unbounded sides use the range for the type
+		r := opts.TemporalRange(field.DataType)
+		if len(lower) == 0 && r.Min.After(min) && r.Min.Before(max) {
+			min = r.Min
+		}
+		if len(upper) == 0 && r.Max.Before(max) && r.Max.After(min) {
+			max = r.Max
+		}
+		if min.After(max) {
+			return nil, fmt.Errorf("no %s value satisfies the conditions", field.DataType)
+		}
+		r = getters.TemporalRange{Min: min, Max: max, Recent: r.Recent}
+		if field.DataType == "date" {
+			return getters.NewRandomDate(field.ColumnName, r, allowNull, rnd), nil
+		}
+		return getters.NewRandomDateTime(field.ColumnName, field.DatetimePrecision.Int64, r, allowNull, rnd), nil
+	}
+
+	if field.DataType == "decimal" && field.NumericScale.Valid {
+		return makeDecimalRangeGetter(field, lower, upper, allowNull, rnd)
+	}
+
+	var typeMin, typeMax float64
+	scale := int64(0)
+	switch {
+	case field.DataType == "year":
+		typeMin, typeMax = 1901, 2155
+	case getters.IsIntegerType(field.DataType) && field.IsUnsigned():
+		typeMax = float64(getters.UintMaxValue(field.DataType))
+	case getters.IsIntegerType(field.DataType):
+		min, max := getters.IntRange(field.DataType)
+		typeMin, typeMax = float64(min), float64(max)
+	default:
+		// decimal, float and double. Plain float and double fields have a NULL scale
+		scale, typeMax = -1, 1e10
+		if field.DataType == "float" {
+			typeMax = 1e8
+		}
+		if field.NumericScale.Valid {
+			scale = field.NumericScale.Int64
+			typeMax = math.Pow10(int(field.NumericPrecision.Int64-scale)) - math.Pow10(-int(scale))
+		}
+		typeMin = -typeMax
+		if field.IsUnsigned() {
+			typeMin = 0
+		}
+	}
+
+	min, max := typeMin, typeMax
+	for _, b := range lower {
+		v, _ := parseBound(field, b.value)
+		min = math.Max(min, roundBound(v, scale, b.inclusive, 1))
+	}
+	for _, b := range upper {
+		v, _ := parseBound(field, b.value)
+		max = math.Min(max, roundBound(v, scale, b.inclusive, -1))
+	}
+	if min > max {
+		return nil, fmt.Errorf("no %s value satisfies the conditions", field.DataType)
+	}
+
+	switch {
+	case !getters.IsIntegerType(field.DataType) && field.DataType != "year":
+		return getters.NewRandomFloatRange(field.ColumnName, min, max, scale, allowNull, rnd), nil
+	case field.IsUnsigned():
+		umax := getters.UintMaxValue(field.DataType)
+		if max < float64(umax) {
+			umax = uint64(max)
+		}
+		return getters.NewRandomUintRange(field.ColumnName, uint64(min), umax, allowNull, rnd), nil
+	case field.DataType == "year":
+		return getters.NewRandomIntRange(field.ColumnName, int64(min), int64(max), allowNull, rnd), nil
+	}
+	// The int64 limits cannot be converted back from float64
+	imin, imax := getters.IntRange(field.DataType)
+	if min > float64(imin) {
+		imin = int64(min)
+	}
+	if max < float64(imax) {
+		imax = int64(max)
+	}
+	return getters.NewRandomIntRange(field.ColumnName, imin, imax, allowNull, rnd), nil
+}
+
+// makeDecimalRangeGetter returns a getter for the decimal values in a range. The bounds are kept as integers
+// multiplied by 10^scale, so they don't lose digits like float64 values would.
+func makeDecimalRangeGetter(field tableparser.Field, lower, upper []bound, allowNull bool,
+	rnd *rand.Rand) (getters.Getter, error) {
+	scale := field.NumericScale.Int64
+	// The type limit is 10^precision - 1 once multiplied by 10^scale
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(field.NumericPrecision.Int64), nil)
+	max.Sub(max, big.NewInt(1))
+	min := new(big.Int).Neg(max)
+	if field.IsUnsigned() {
+		min.SetInt64(0)
+	}
+
+	unit := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(scale), nil))
+	for _, b := range lower {
+		v, ok := new(big.Rat).SetString(b.value)
+		if !ok {
+			continue
+		}
+		if n := scaledBound(v.Mul(v, unit), b.inclusive, 1); n.Cmp(min) > 0 {
+			min = n
+		}
+	}
+	for _, b := range upper {
+		v, ok := new(big.Rat).SetString(b.value)
+		if !ok {
+			continue
+		}
+		if n := scaledBound(v.Mul(v, unit), b.inclusive, -1); n.Cmp(max) < 0 {
+			max = n
+		}
+	}
+	if min.Cmp(max) > 0 {
+		return nil, fmt.Errorf("no %s value satisfies the conditions", field.DataType)
+	}
+	return getters.NewRandomDecimalRange(field.ColumnName, min, max, scale, allowNull, rnd), nil
+}
+
+// scaledBound is roundBound for bounds multiplied by 10^scale: it returns the first integer after a lower bound
+// (dir = 1) or before an upper bound (dir = -1)
+func scaledBound(v *big.Rat, inclusive bool, dir int) *big.Int {
+	// floor of v; Quo truncates towards zero
+	n := new(big.Int).Quo(v.Num(), v.Denom())
+	if v.Sign() < 0 && !v.IsInt() {
+		n.Sub(n, big.NewInt(1))
+	}
+	switch {
+	case dir > 0 && (!inclusive || !v.IsInt()):
+		n.Add(n, big.NewInt(1))
+	case dir < 0 && !inclusive && v.IsInt():
+		n.Sub(n, big.NewInt(1))
+	}
+	return n
+}
+
+// roundBound returns the first value having scale decimals after a lower bound (dir = 1) or before an upper
+// bound (dir = -1). The bound itself is included if inclusive is true. If scale is negative, values can have any
+// number of decimals.
+func roundBound(v float64, scale int64, inclusive bool, dir float64) float64 {
+	if scale < 0 {
+		if inclusive {
+			return v
+		}
+		return math.Nextafter(v, dir*math.Inf(1))
+	}
+	step := math.Pow10(-int(scale))
+	// Rounding to 6 decimals removes the errors of the division, like 0.07 / 0.01 = 7.000000000000001
+	n := math.Round(v/step*1e6) / 1e6
+	if dir > 0 {
+		if inclusive {
+			return math.Ceil(n) * step
+		}
+		return (math.Floor(n) + 1) * step
+	}
+	if inclusive {
+		return math.Floor(n) * step
+	}
+	return (math.Ceil(n) - 1) * step
+}
+
+// parseBound returns a bound of a range condition as a number. For temporal fields, it only checks the
+// bound is a valid date.
+func parseBound(field tableparser.Field, value string) (float64, error) {
+	if isTemporalType(field.DataType) {
+		_, err := getters.ParseDateTime(value)
+		return 0, err
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value %q", field.DataType, value)
+	}
+	return v, nil
+}
+
+// condition returns a getters.Condition for a condition from a CHECK constraint. NULL values only
+// violate IS NOT NULL conditions
+func condition(cond tableparser.CheckCondition) getters.Condition {
+	return func(v interface{}) bool {
+		switch {
+		case cond.Op == "is null":
+			return v == nil
+		case cond.Op == "is not null":
+			return v != nil
+		case v == nil:
+			return true
+		}
+		if cond.Function != "" {
+			v = valueLength(v, cond.Function)
+		}
+		switch cond.Op {
+		case "in":
+			for _, value := range cond.Values {
+				if getters.Compare("=", v, value) {
+					return true
+				}
+			}
+			return false
+		case "not in":
+			for _, value := range cond.Values {
+				if getters.Compare("=", v, value) {
+					return false
+				}
+			}
+			return true
+		case "between":
+			return getters.Compare(">=", v, cond.Values[0]) && getters.Compare("<=", v, cond.Values[1])
+		}
+		return getters.Compare(cond.Op, v, cond.Values[0])
+	}
+}
+
+// valueLength returns the length of a string or []byte value in bytes or, for char_length, in chars
+func valueLength(v interface{}, function string) int64 {
+	var s string
+	switch val := v.(type) {
+	case string:
+		s = val
+	case []byte:
+		s = string(val)
+	default:
+		s = fmt.Sprintf("%v", val)
+	}
+	if function == "char_length" {
+		return int64(utf8.RuneCountInString(s))
+	}
+	return int64(len(s))
+}
+
+// lengthBounds returns the min and max length allowed by a length condition. max is -1 if there is no max length.
+// Lengths are counted in bytes for LENGTH() but string getters generate ASCII strings, so they are the same.
+func lengthBounds(cond tableparser.CheckCondition) (int64, int64) {
+	values := make([]int64, len(cond.Values))
+	for i, v := range cond.Values {
+		values[i], _ = strconv.ParseInt(v, 10, 64)
+	}
+	switch cond.Op {
+	case "=":
+		return values[0], values[0]
+	case ">":
+		return values[0] + 1, -1
+	case ">=":
+		return values[0], -1
+	case "<":
+		return 0, values[0] - 1
+	case "<=":
+		return 0, values[0]
+	case "between":
+		return values[0], values[1]
+	}
+	return 0, -1
+}
+
+// isTemporalType returns true for the date, datetime and timestamp types
+func isTemporalType(dataType string) bool {
+	return dataType == "date" || dataType == "datetime" || dataType == "timestamp"
+}
+
+// isOrderedType returns true for the types supporting range conditions and comparisons between fields
+func isOrderedType(dataType string) bool {
+	switch dataType {
+	case "decimal", "float", "double", "year":
+		return true
+	}
+	return getters.IsIntegerType(dataType) || isTemporalType(dataType)
+}
+
+// isCheckedType returns true for the types having values formatted by getters.Checked like the getters
+// of the type do. Spatial, bit, time and json values would lose their format
+func isCheckedType(dataType string) bool {
+	return isOrderedType(dataType) || getters.IsStringType(dataType) || dataType == "enum" || dataType == "set"
+}
@@ -0,0 +1,102 @@
+package checks
+
+import (
+	"database/sql"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/Percona-Lab/mysql_random_data_load/internal/getters"
+	"github.com/Percona-Lab/mysql_random_data_load/tableparser"
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestApply(t *testing.T) {
+	table := &tableparser.Table{
+		Schema: "test",
+		Name:   "t1",
+		Fields: []tableparser.Field{
+			{ColumnName: "qty", DataType: "int", ColumnType: "int", IsNullable: true},
+			{ColumnName: "price", DataType: "decimal", ColumnType: "decimal(5,2) unsigned",
+				NumericPrecision: sql.NullInt64{Int64: 5, Valid: true}, NumericScale: sql.NullInt64{Int64: 2, Valid: true}},
+			{ColumnName: "max_price", DataType: "decimal", ColumnType: "decimal(5,2) unsigned",
+				NumericPrecision: sql.NullInt64{Int64: 5, Valid: true}, NumericScale: sql.NullInt64{Int64: 2, Valid: true}},
+			{ColumnName: "location", DataType: "point", ColumnType: "point"},
+		},
+		Checks: []tableparser.Check{
+			{Name: "c1", Clause: "((`qty` > 0) or (`qty` is null))", Enforced: true},
+			{Name: "c2", Clause: "(`price` between 1 and 9.99)", Enforced: true},
+			{Name: "c3", Clause: "(`max_price` >= `price`)", Enforced: true},
+			{Name: "c4", Clause: "(`location` is not null)", Enforced: true},
+		},
+	}
+	rnd := rand.New(rand.NewSource(1))
+	location := getters.NewRandomGeometry("location", "point", 0, getters.BoundingBox{}, false, rnd)
+	values := []getters.Getter{
+		getters.NewRandomIntRange("qty", -100, 100, true, rnd),
+		getters.NewRandomFloatRange("price", 0, 999.99, 2, false, rnd),
+		getters.NewRandomFloatRange("max_price", 0, 999.99, 2, false, rnd),
+		location,
+	}
+	opts := Options{Seed: 1, TemporalRange: getters.DefaultTemporalRange}
+	tu.Ok(t, Apply(table, table.Fields, values, opts))
+
+	// The conditions on spatial fields are not enforced, so the field keeps its getter
+	tu.Equals(t, getters.Getter(location), values[3])
+	nulls := 0
+	for i := 0; i < 1000; i++ {
+		qty := values[0].Value()
+		if qty == nil {
+			nulls++
+		} else {
+			tu.Assert(t, qty.(int64) > 0, "qty %d out of range", qty)
+		}
+		price, _ := strconv.ParseFloat(values[1].String(), 64)
+		tu.Assert(t, price >= 1 && price <= 9.99, "price %v out of range", price)
+		maxPrice, _ := strconv.ParseFloat(values[2].String(), 64)
+		tu.Assert(t, maxPrice >= price, "max_price %v is less than price %v", maxPrice, price)
+	}
+	tu.Assert(t, nulls > 0, "qty has no NULL values")
+
+	table.Checks = []tableparser.Check{{Name: "c1", Clause: "(`qty` between 10 and 5)", Enforced: true}}
+	tu.NotOk(t, Apply(table, table.Fields, values, opts))
+
+	// The fields must match the getters
+	tu.NotOk(t, Apply(table, table.Fields[:1], values, opts))
+}
+
+func TestApplyDecimal(t *testing.T) {
+	table := &tableparser.Table{
+		Schema: "test",
+		Name:   "t1",
+		Fields: []tableparser.Field{
+			{ColumnName: "amount", DataType: "decimal", ColumnType: "decimal(30,10)",
+				NumericPrecision: sql.NullInt64{Int64: 30, Valid: true}, NumericScale: sql.NullInt64{Int64: 10, Valid: true}},
+			{ColumnName: "rate", DataType: "decimal", ColumnType: "decimal(10,8)",
+				NumericPrecision: sql.NullInt64{Int64: 10, Valid: true}, NumericScale: sql.NullInt64{Int64: 8, Valid: true}},
+		},
+		Checks: []tableparser.Check{
+			{Name: "c1", Clause: "((`amount` > 12345678901234567890.0000000001) and (`amount` < 12345678901234567890.0000000005))",
+				Enforced: true},
+			{Name: "c2", Clause: "(`rate` between 0.00001 and 0.00002)", Enforced: true},
+		},
+	}
+	rnd := rand.New(rand.NewSource(1))
+	values := []getters.Getter{
+		getters.NewRandomDecimal("amount", 30, 10, false, false, rnd),
+		getters.NewRandomDecimal("rate", 10, 8, false, false, rnd),
+	}
+	tu.Ok(t, Apply(table, table.Fields, values, Options{Seed: 1, TemporalRange: getters.DefaultTemporalRange}))
+
+	want := map[string]bool{
+		"12345678901234567890.0000000002": true,
+		"12345678901234567890.0000000003": true,
+		"12345678901234567890.0000000004": true,
+	}
+	for i := 0; i < 100; i++ {
+		amount := values[0].String()
+		tu.Assert(t, want[amount], "amount %s out of range", amount)
+		rate := values[1].Quote()
+		tu.Assert(t, len(rate) == 10 && rate >= "0.00001000" && rate <= "0.00002000", "invalid rate %s", rate)
+	}
+}
@@ -0,0 +1,407 @@
+package generators
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Percona-Lab/mysql_random_data_load/internal/getters"
+	"gopkg.in/yaml.v2"
+)
+
+// ValidGenerators is the list of generator names that can be used in a generators file
+var ValidGenerators = []string{"int", "string", "date", "date_in_range", "values", "set", "binary", "json", "geometry",
+	"sequence", "uuid", "ulid", "fake", "samples"}
+
+// ValidDistributions is the list of distributions that can be used with the int, date_in_range, values and
+// samples generators
+var ValidDistributions = []string{"uniform", "zipf", "normal", "exponential", "weighted"}
+
+// Spec holds the generator definition for a single column
+type Spec struct {
+	Generator string   `json:"generator" yaml:"generator"`
+	Min       Param    `json:"min,omitempty" yaml:"min,omitempty"`
+	Max       Param    `json:"max,omitempty" yaml:"max,omitempty"`
+	Length    int64    `json:"length,omitempty" yaml:"length,omitempty"`
+	NullRatio *float64 `json:"null_ratio,omitempty" yaml:"null_ratio,omitempty"`
+	Values    []string `json:"values,omitempty" yaml:"values,omitempty"`
+	// Parameters for the json generator
+	Depth      int             `json:"depth,omitempty" yaml:"depth,omitempty"`
+	Keys       []string        `json:"keys,omitempty" yaml:"keys,omitempty"`
+	ArraySize  int             `json:"array_size,omitempty" yaml:"array_size,omitempty"`
+	Schema     json.RawMessage `json:"schema,omitempty" yaml:"-"`
+	SchemaFile string          `json:"schema_file,omitempty" yaml:"schema_file,omitempty"`
+	Sample     json.RawMessage `json:"sample,omitempty" yaml:"-"`
+	SampleFile string          `json:"sample_file,omitempty" yaml:"sample_file,omitempty"`
+	// Parameters for the geometry generator
+	BoundingBox []float64 `json:"bbox,omitempty" yaml:"bbox,omitempty"`
+	// UUID version for the uuid generator: 1, 4 or 7. Default: 4
+	Version int `json:"version,omitempty" yaml:"version,omitempty"`
+	// Kind of values for the fake generator, like email or city
+	Kind string `json:"kind,omitempty" yaml:"kind,omitempty"`
+	// Distribution of the values of the int, date_in_range, values and samples generators. Default: uniform
+	Distribution *Distribution `json:"distribution,omitempty" yaml:"distribution,omitempty"`
+}
+
+// Distribution holds the parameters of a skewed distribution of values
+type Distribution struct {
+	Type string `json:"type" yaml:"type"`
+	// Exponent for the zipf distribution, greater than 1. Default: 1.1
+	S float64 `json:"s,omitempty" yaml:"s,omitempty"`
+	// Mean and standard deviation for the normal distribution, in the units of the values
+	Mean   Param `json:"mean,omitempty" yaml:"mean,omitempty"`
+	StdDev Param `json:"stddev,omitempty" yaml:"stddev,omitempty"`
+	// Decay rate over the whole range for the exponential distribution. Default: 5
+	Rate float64 `json:"rate,omitempty" yaml:"rate,omitempty"`
+	// Reverse makes the zipf and exponential distributions skewed toward the max value instead of the min
+	Reverse bool `json:"reverse,omitempty" yaml:"reverse,omitempty"`
+	// Weights of the values, in order, for the weighted distribution
+	Weights []float64 `json:"weights,omitempty" yaml:"weights,omitempty"`
+}
+
+// Specs maps a fully qualified column name (schema.table.column) to its generator
+type Specs map[string]Spec
+
+// Param holds a generator parameter. In the generators file it can be written
+// either as a number or as a string, for example: "min": 1 or "min": "2019-01-01"
+type Param string
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (p *Param) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*p = Param(s)
+		return nil
+	}
+	if string(data) == "null" {
+		*p = ""
+		return nil
+	}
+	*p = Param(data)
+	return nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface. The schema and sample documents of the json
+// generator are converted to JSON
+func (s *Spec) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain Spec
+	if err := unmarshal((*plain)(s)); err != nil {
+		return err
+	}
+	var docs struct {
+		Schema *yamlDocument `yaml:"schema"`
+		Sample *yamlDocument `yaml:"sample"`
+	}
+	if err := unmarshal(&docs); err != nil {
+		return err
+	}
+	var err error
+	if docs.Schema != nil {
+		if s.Schema, err = json.Marshal(docs.Schema); err != nil {
+			return err
+		}
+	}
+	if docs.Sample != nil {
+		if s.Sample, err = json.Marshal(docs.Sample); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// yamlDocument is a YAML document embedded in a generators file. Its mappings keep the order of their keys,
+// so samples of the json generator generate keys in the same order
+type yamlDocument struct {
+	value interface{}
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface
+func (d *yamlDocument) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var m yaml.MapSlice
+	if err := unmarshal(&m); err == nil {
+		d.value = m
+		return nil
+	}
+	var s []yamlDocument
+	if err := unmarshal(&s); err == nil {
+		d.value = s
+		return nil
+	}
+	return unmarshal(&d.value)
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (d yamlDocument) MarshalJSON() ([]byte, error) {
+	return yamlToJSON(d.value)
+}
+
+// yamlToJSON returns the JSON encoding of a value decoded from YAML
+func yamlToJSON(v interface{}) ([]byte, error) {
+	switch val := v.(type) {
+	case yaml.MapSlice:
+		buf := []byte{'{'}
+		for i, item := range val {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			key, err := json.Marshal(fmt.Sprint(item.Key))
+			if err != nil {
+				return nil, err
+			}
+			value, err := yamlToJSON(item.Value)
+			if err != nil {
+				return nil, err
+			}
+			buf = append(append(append(buf, key...), ':'), value...)
+		}
+		return append(buf, '}'), nil
+	case []interface{}:
+		buf := []byte{'['}
+		for i, item := range val {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			value, err := yamlToJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			buf = append(buf, value...)
+		}
+		return append(buf, ']'), nil
+	case []yamlDocument:
+		items := make([]interface{}, len(val))
+		for i, item := range val {
+			items[i] = item.value
+		}
+		return yamlToJSON(items)
+	}
+	return json.Marshal(v)
+}
+
+// Int64 returns the parameter as an int64 or def if the parameter is empty
+func (p Param) Int64(def int64) (int64, error) {
+	if p == "" {
+		return def, nil
+	}
+	return strconv.ParseInt(string(p), 10, 64)
+}
+
+// Uint64 returns the parameter as an uint64 or def if the parameter is empty
+func (p Param) Uint64(def uint64) (uint64, error) {
+	if p == "" {
+		return def, nil
+	}
+	return strconv.ParseUint(string(p), 10, 64)
+}
+
+// Load reads and validates a generators file. Files having the .yaml or .yml extension are read as YAML,
+// other files as JSON
+func Load(filename string) (Specs, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read generators file %q: %s", filename, err)
+	}
+
+	specs := make(Specs)
+	if ext := strings.ToLower(filepath.Ext(filename)); ext == ".yaml" || ext == ".yml" {
+		err = yaml.Unmarshal(data, &specs)
+	} else {
+		err = json.Unmarshal(data, &specs)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse generators file %q: %s", filename, err)
+	}
+
+	for column, spec := range specs {
+		if strings.Count(column, ".") != 2 {
+			return nil, fmt.Errorf("invalid column name %q. Column names must be in the form schema.table.column", column)
+		}
+		if err := spec.loadFiles(filepath.Dir(filename)); err != nil {
+			return nil, fmt.Errorf("invalid generator for column %q: %s", column, err)
+		}
+		if err := spec.validate(); err != nil {
+			return nil, fmt.Errorf("invalid generator for column %q: %s", column, err)
+		}
+		specs[column] = spec
+	}
+
+	return specs, nil
+}
+
+// Get returns the generator spec for a column, if it was defined
+func (s Specs) Get(schema, table, column string) (Spec, bool) {
+	spec, ok := s[schema+"."+table+"."+column]
+	return spec, ok
+}
+
+func (s Spec) validate() error {
+	if !isValidGenerator(s.Generator) {
+		return fmt.Errorf("unknown generator %q. Valid generators are: %s", s.Generator,
+			strings.Join(ValidGenerators, ", "))
+	}
+	if s.NullRatio != nil && (*s.NullRatio < 0 || *s.NullRatio > 1) {
+		return fmt.Errorf("null_ratio must be between 0 and 1")
+	}
+	if s.Length < 0 {
+		return fmt.Errorf("length cannot be negative")
+	}
+	if s.Depth < 0 || s.ArraySize < 0 {
+		return fmt.Errorf("depth and array_size cannot be negative")
+	}
+	if len(s.Schema) > 0 && len(s.Sample) > 0 {
+		return fmt.Errorf("schema and sample cannot be used at the same time")
+	}
+	if len(s.Schema) > 0 && !json.Valid(s.Schema) {
+		return fmt.Errorf("the schema is not a valid JSON document")
+	}
+	if len(s.Sample) > 0 && !json.Valid(s.Sample) {
+		return fmt.Errorf("the sample is not a valid JSON document")
+	}
+	if s.BoundingBox != nil && (len(s.BoundingBox) != 4 ||
+		s.BoundingBox[0] >= s.BoundingBox[2] || s.BoundingBox[1] >= s.BoundingBox[3]) {
+		return fmt.Errorf("bbox must be [minX, minY, maxX, maxY] and min values must be lower than max values")
+	}
+	if min, err := s.Min.Int64(0); s.Generator == "set" && err == nil && min < 0 {
+		return fmt.Errorf("the minimum number of members cannot be negative")
+	}
+	if s.Version != 0 && s.Version != 1 && s.Version != 4 && s.Version != 7 {
+		return fmt.Errorf("invalid UUID version %d. Valid versions are 1, 4 and 7", s.Version)
+	}
+	if s.Generator == "fake" && !isFakeKind(s.Kind) {
+		return fmt.Errorf("invalid kind %q for the fake generator. Valid kinds are: %s", s.Kind,
+			strings.Join(getters.FakeKinds(), ", "))
+	}
+	if _, err := s.Min.Int64(0); s.Generator == "sequence" && err != nil {
+		return fmt.Errorf("invalid sequence start %q: %s", s.Min, err)
+	}
+	if s.Distribution != nil {
+		if err := s.validateDistribution(); err != nil {
+			return err
+		}
+	}
+	if s.Generator == "int" || s.Generator == "binary" || s.Generator == "set" {
+		return s.validateIntRange()
+	}
+	return nil
+}
+
+func (s Spec) validateDistribution() error {
+	d := s.Distribution
+	switch s.Generator {
+	case "int", "date_in_range", "values", "samples":
+	default:
+		return fmt.Errorf("the %s generator doesn't support distributions", s.Generator)
+	}
+	switch d.Type {
+	case "uniform", "normal":
+	case "zipf":
+		if d.S != 0 && d.S <= 1 {
+			return fmt.Errorf("the zipf exponent s must be greater than 1")
+		}
+	case "exponential":
+		if d.Rate < 0 {
+			return fmt.Errorf("the exponential rate cannot be negative")
+		}
+	case "weighted":
+		if s.Generator == "date_in_range" {
+			return fmt.Errorf("the weighted distribution cannot be used with the date_in_range generator")
+		}
+		total := 0.0
+		for _, w := range d.Weights {
+			if w < 0 {
+				return fmt.Errorf("weights cannot be negative")
+			}
+			total += w
+		}
+		if total == 0 {
+			return fmt.Errorf("the weighted distribution needs at least one positive weight")
+		}
+		if s.Generator == "values" && len(s.Values) > 0 && len(d.Weights) != len(s.Values) {
+			return fmt.Errorf("there are %d weights for %d values", len(d.Weights), len(s.Values))
+		}
+	default:
+		return fmt.Errorf("unknown distribution %q. Valid distributions are: %s", d.Type,
+			strings.Join(ValidDistributions, ", "))
+	}
+	if len(d.Weights) > 0 && d.Type != "weighted" {
+		return fmt.Errorf("weights can only be used with the weighted distribution")
+	}
+	return nil
+}
+
+// loadFiles reads the JSON schema and sample files. Relative paths are relative to the
+// directory of the generators file
+func (s *Spec) loadFiles(dir string) error {
+	var err error
+	if s.SchemaFile != "" {
+		if s.Schema, err = readFile(dir, s.SchemaFile); err != nil {
+			return err
+		}
+	}
+	if s.SampleFile != "" {
+		if s.Sample, err = readFile(dir, s.SampleFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readFile(dir, filename string) ([]byte, error) {
+	if !filepath.IsAbs(filename) {
+		filename = filepath.Join(dir, filename)
+	}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read file %q: %s", filename, err)
+	}
+	return data, nil
+}
+
+func (s Spec) validateIntRange() error {
+	min, errMin := s.Min.Int64(0)
+	max, errMax := s.Max.Int64(min)
+	if errMin == nil && errMax == nil {
+		if min > max {
+			return fmt.Errorf("min (%d) is greater than max (%d)", min, max)
+		}
+		return nil
+	}
+
+	// Values greater than the int64 max value are valid for unsigned bigint fields
+	umin, err := s.Min.Uint64(0)
+	if err != nil {
+		return fmt.Errorf("invalid min value %q: %s", s.Min, err)
+	}
+	umax, err := s.Max.Uint64(umin)
+	if err != nil {
+		return fmt.Errorf("invalid max value %q: %s", s.Max, err)
+	}
+	if umin > umax {
+		return fmt.Errorf("min (%d) is greater than max (%d)", umin, umax)
+	}
+	return nil
+}
+
+func isFakeKind(kind string) bool {
+	for _, k := range getters.FakeKinds() {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func isValidGenerator(name string) bool {
+	for _, g := range ValidGenerators {
+		if g == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package generators
+
+import (
+	"path/filepath"
+	"testing"
+
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestLoad(t *testing.T) {
+	specs, err := Load(filepath.Join("testdata", "generators.json"))
+	tu.Ok(t, err)
+	tu.Assert(t, len(specs) == 4, "Invalid number of specs. Have %d, want 4", len(specs))
+
+	spec, ok := specs.Get("sakila", "film", "rental_duration")
+	tu.Assert(t, ok, "Missing spec for rental_duration")
+	tu.Equals(t, "int", spec.Generator)
+	tu.Equals(t, Param("1"), spec.Min)
+	tu.Equals(t, Param("7"), spec.Max)
+
+	spec, ok = specs.Get("sakila", "film", "last_update")
+	tu.Assert(t, ok, "Missing spec for last_update")
+	tu.Equals(t, Param("2019-01-01"), spec.Min)
+
+	spec, ok = specs.Get("sakila", "film", "original_language_id")
+	tu.Assert(t, ok, "Missing spec for original_language_id")
+	tu.Equals(t, []string{"1", "2", "3"}, spec.Values)
+	tu.Equals(t, 0.5, *spec.NullRatio)
+
+	_, ok = specs.Get("sakila", "film", "description")
+	tu.Assert(t, !ok, "There shouldn't be a spec for description")
+}
+
+func TestLoadInvalid(t *testing.T) {
+	_, err := Load(filepath.Join("testdata", "invalid.json"))
+	tu.NotOk(t, err)
+
+	_, err = Load(filepath.Join("testdata", "not_exists.json"))
+	tu.NotOk(t, err)
+}
+
+func TestLoadJSON(t *testing.T) {
+	specs, err := Load(filepath.Join("testdata", "json.json"))
+	tu.Ok(t, err)
+
+	spec, ok := specs.Get("test", "customers", "attributes")
+	tu.Assert(t, ok, "Missing spec for attributes")
+	tu.Assert(t, len(spec.Schema) > 0, "The schema file was not loaded")
+
+	spec, ok = specs.Get("test", "customers", "preferences")
+	tu.Assert(t, ok, "Missing spec for preferences")
+	tu.Equals(t, `{"newsletter": true, "languages": ["en", "es"]}`, string(spec.Sample))
+
+	spec, ok = specs.Get("test", "customers", "extra")
+	tu.Assert(t, ok, "Missing spec for extra")
+	tu.Equals(t, 3, spec.Depth)
+	tu.Equals(t, []string{"a", "b", "c"}, spec.Keys)
+	tu.Equals(t, 2, spec.ArraySize)
+}
+
+func TestLoadYAML(t *testing.T) {
+	want, err := Load(filepath.Join("testdata", "generators.json"))
+	tu.Ok(t, err)
+	specs, err := Load(filepath.Join("testdata", "generators.yaml"))
+	tu.Ok(t, err)
+	tu.Equals(t, want, specs)
+
+	specs, err = Load(filepath.Join("testdata", "json.yaml"))
+	tu.Ok(t, err)
+	tu.Equals(t, `{"newsletter":true,"languages":["en","es"],"address":{"city":"Madrid","zip":"28001"}}`,
+		string(specs["test.customers.preferences"].Sample))
+	tu.Equals(t, []string{"a", "b", "c"}, specs["test.customers.extra"].Keys)
+	tu.Equals(t, 2, specs["test.customers.extra"].ArraySize)
+	tu.Equals(t, []string{"active", "blocked, manually", "it's"}, specs["test.customers.status"].Values)
+	tu.Equals(t, &Distribution{Type: "weighted", Weights: []float64{8, 1, 1}}, specs["test.customers.status"].Distribution)
+	tu.Equals(t, `{"type":"object","properties":{"a":{"type":"integer","minimum":1}},"required":["a"]}`,
+		string(specs["test.customers.notes"].Schema))
+}
+
+func TestValidateSet(t *testing.T) {
+	tu.Ok(t, Spec{Generator: "set", Min: "1", Max: "2"}.validate())
+	tu.NotOk(t, Spec{Generator: "set", Min: "-1"}.validate())
+	tu.NotOk(t, Spec{Generator: "set", Min: "3", Max: "2"}.validate())
+}
+
+func TestValidatePrimaryKeys(t *testing.T) {
+	tu.Ok(t, Spec{Generator: "uuid", Version: 7}.validate())
+	tu.NotOk(t, Spec{Generator: "uuid", Version: 5}.validate())
+	tu.Ok(t, Spec{Generator: "sequence", Min: "1000"}.validate())
+	tu.NotOk(t, Spec{Generator: "sequence", Min: "a"}.validate())
+}
+
+func TestValidateFake(t *testing.T) {
+	tu.Ok(t, Spec{Generator: "fake", Kind: "email"}.validate())
+	tu.NotOk(t, Spec{Generator: "fake"}.validate())
+	tu.NotOk(t, Spec{Generator: "fake", Kind: "nope"}.validate())
+}
+
+func TestValidateDistribution(t *testing.T) {
+	tu.Ok(t, Spec{Generator: "int", Distribution: &Distribution{Type: "zipf", S: 1.5}}.validate())
+	tu.Ok(t, Spec{Generator: "samples", Distribution: &Distribution{Type: "exponential", Reverse: true}}.validate())
+	tu.Ok(t, Spec{Generator: "date_in_range", Distribution: &Distribution{Type: "normal", Mean: "-30d"}}.validate())
+	tu.Ok(t, Spec{Generator: "values", Values: []string{"a", "b"},
+		Distribution: &Distribution{Type: "weighted", Weights: []float64{9, 1}}}.validate())
+	// Weights for the members of an enum column
+	tu.Ok(t, Spec{Generator: "values", Distribution: &Distribution{Type: "weighted", Weights: []float64{1, 0, 2}}}.validate())
+
+	tu.NotOk(t, Spec{Generator: "string", Distribution: &Distribution{Type: "zipf"}}.validate())
+	tu.NotOk(t, Spec{Generator: "int", Distribution: &Distribution{Type: "pareto"}}.validate())
+	tu.NotOk(t, Spec{Generator: "int", Distribution: &Distribution{Type: "zipf", S: 0.5}}.validate())
+	tu.NotOk(t, Spec{Generator: "int", Distribution: &Distribution{Type: "exponential", Rate: -1}}.validate())
+	tu.NotOk(t, Spec{Generator: "int", Distribution: &Distribution{Type: "normal", Weights: []float64{1}}}.validate())
+	tu.NotOk(t, Spec{Generator: "values", Values: []string{"a", "b"},
+		Distribution: &Distribution{Type: "weighted", Weights: []float64{1}}}.validate())
+	tu.NotOk(t, Spec{Generator: "values", Distribution: &Distribution{Type: "weighted", Weights: []float64{0, -1}}}.validate())
+}
+
+func TestInfer(t *testing.T) {
+	type column struct {
+		name, comment, dataType string
+		maxLength               int64
+	}
+	tests := map[column]Spec{
+		{"email", "", "varchar", 50}:                              {Generator: "fake", Kind: "email"},
+		{"customer_email_address", "", "varchar", 255}:            {Generator: "fake", Kind: "email"},
+		{"FirstName", "", "varchar", 45}:                          {Generator: "fake", Kind: "first_name"},
+		{"ip_address", "", "varchar", 45}:                         {Generator: "fake", Kind: "ip"},
+		{"postal_code", "", "char", 10}:                           {Generator: "fake", Kind: "zip"},
+		{"address", "", "varchar", 50}:                            {Generator: "fake", Kind: "street_address"},
+		{"contact", "Phone number of the contact", "varchar", 20}: {Generator: "fake", Kind: "phone"},
+		{"uuid", "", "char", 36}:                                  {Generator: "uuid"},
+		{"guid", "", "binary", 16}:                                {Generator: "uuid"},
+		{"created_at", "", "datetime", 0}:                         {Generator: "date_in_range", Min: "-2y", Max: "now"},
+		{"last_update", "", "timestamp", 0}:                       {Generator: "date_in_range", Min: "-2y", Max: "now"},
+		{"birth_date", "", "date", 0}:                             {Generator: "date_in_range", Min: "-90y", Max: "-18y"},
+	}
+	for c, want := range tests {
+		spec, ok := Infer(c.name, c.comment, c.dataType, c.maxLength)
+		tu.Assert(t, ok, "No generator inferred for %s", c.name)
+		tu.Equals(t, want, spec)
+		tu.Ok(t, spec.validate())
+	}
+
+	for _, c := range []column{
+		{"name", "", "varchar", 45},
+		{"email", "", "varchar", 10},
+		{"email", "", "int", 0},
+		{"country_id", "", "varchar", 45},
+		{"mailbox", "", "varchar", 45},
+		{"release_year", "", "year", 0},
+	} {
+		_, ok := Infer(c.name, c.comment, c.dataType, c.maxLength)
+		tu.Assert(t, !ok, "Unexpected generator inferred for %s", c.name)
+	}
+}
@@ -0,0 +1,149 @@
+package generators
+
+import (
+	"unicode"
+)
+
+// rule maps column names to a kind of fake values. A rule matches a column if any of its words
+// sequences is in the column name, like email in customer_email or first name in FirstName
+type rule struct {
+	kind      string
+	minLength int64
+	words     [][]string
+}
+
+// stringRules are checked in order, so rules for names like email_address come before the rule for address
+var stringRules = []rule{
+	{"email", 16, [][]string{{"email"}, {"e", "mail"}}},
+	{"uuid", 36, [][]string{{"uuid"}, {"guid"}}},
+	{"ipv6", 39, [][]string{{"ipv6"}, {"ip6"}}},
+	{"ip", 15, [][]string{{"ip"}, {"ipv4"}, {"ip4"}, {"ipaddr"}, {"ip", "addr"}}},
+	{"url", 24, [][]string{{"url"}, {"uri"}, {"website"}, {"homepage"}, {"web", "site"}, {"home", "page"}, {"link"}}},
+	{"domain", 12, [][]string{{"domain"}, {"hostname"}, {"host"}}},
+	{"user_agent", 40, [][]string{{"user", "agent"}, {"useragent"}}},
+	{"user_name", 8, [][]string{{"user", "name"}, {"username"}, {"login"}, {"nickname"}, {"nick"}}},
+	{"first_name", 6, [][]string{{"first", "name"}, {"firstname"}, {"fname"}, {"given", "name"}, {"forename"}}},
+	{"last_name", 6, [][]string{{"last", "name"}, {"lastname"}, {"lname"}, {"surname"}, {"family", "name"}}},
+	{"full_name", 12, [][]string{{"full", "name"}, {"fullname"}, {"contact", "name"}, {"customer", "name"},
+		{"person", "name"}}},
+	{"phone", 12, [][]string{{"phone"}, {"telephone"}, {"tel"}, {"mobile"}, {"cellphone"}, {"fax"}}},
+	{"iban", 34, [][]string{{"iban"}}},
+	{"credit_card", 16, [][]string{{"credit", "card"}, {"creditcard"}, {"card", "number"}, {"cc", "number"}}},
+	{"zip", 5, [][]string{{"zip"}, {"zipcode"}, {"postcode"}, {"postal", "code"}, {"post", "code"}}},
+	{"city", 10, [][]string{{"city"}, {"town"}}},
+	{"state", 10, [][]string{{"state"}, {"province"}, {"region"}}},
+	{"country", 10, [][]string{{"country"}}},
+	{"street_address", 20, [][]string{{"street"}, {"address"}, {"addr"}}},
+	{"company", 10, [][]string{{"company"}, {"organization"}, {"organisation"}, {"employer"}}},
+	{"job_title", 12, [][]string{{"job", "title"}, {"jobtitle"}, {"occupation"}, {"position"}}},
+	{"currency", 3, [][]string{{"currency"}}},
+	{"color", 6, [][]string{{"color"}, {"colour"}}},
+	{"language", 10, [][]string{{"language"}, {"lang"}}},
+	{"gender", 4, [][]string{{"gender"}, {"sex"}}},
+	{"product", 12, [][]string{{"product", "name"}, {"product"}}},
+	{"title", 20, [][]string{{"title"}, {"subject"}, {"headline"}}},
+	{"paragraph", 200, [][]string{{"description"}, {"comment"}, {"comments"}, {"notes"}, {"note"}, {"body"},
+		{"summary"}, {"bio"}}},
+}
+
+// temporalRules maps date, datetime and timestamp column names to ranges relative to now
+var temporalRules = []struct {
+	min, max string
+	words    [][]string
+}{
+	{"-90y", "-18y", [][]string{{"birth"}, {"birthday"}, {"birthdate"}, {"dob"}, {"born"}}},
+	{"now", "+1y", [][]string{{"expires"}, {"expiry"}, {"expiration"}, {"expire"}, {"due"}, {"valid", "until"}}},
+	{"-2y", "now", [][]string{{"created"}, {"updated"}, {"modified"}, {"deleted"}, {"inserted"}, {"changed"},
+		{"last", "update"}, {"last", "login"}, {"last", "seen"}, {"at"}, {"timestamp"}}},
+}
+
+// Infer returns a generator for a column based on its name and, if the name doesn't match any rule,
+// on the words in its comment. For example, varchar columns named email get email addresses and
+// datetime columns named created_at get dates from the last 2 years. maxLength is the maximum
+// length of string columns. Columns too short for the values of a kind don't get its generator.
+func Infer(column, comment, dataType string, maxLength int64) (Spec, bool) {
+	for _, text := range []string{column, comment} {
+		words := splitWords(text)
+		if len(words) == 0 || words[len(words)-1] == "id" {
+			continue
+		}
+		if spec, ok := inferFromWords(words, dataType, maxLength); ok {
+			return spec, true
+		}
+	}
+	return Spec{}, false
+}
+
+func inferFromWords(words []string, dataType string, maxLength int64) (Spec, bool) {
+	switch dataType {
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext":
+		for _, r := range stringRules {
+			if !matchAny(words, r.words) {
+				continue
+			}
+			if maxLength < r.minLength {
+				return Spec{}, false
+			}
+			if r.kind == "uuid" {
+				return Spec{Generator: "uuid"}, true
+			}
+			return Spec{Generator: "fake", Kind: r.kind}, true
+		}
+	case "binary", "varbinary":
+		if maxLength >= 16 && matchAny(words, [][]string{{"uuid"}, {"guid"}}) {
+			return Spec{Generator: "uuid"}, true
+		}
+	case "date", "datetime", "timestamp":
+		for _, r := range temporalRules {
+			if matchAny(words, r.words) {
+				return Spec{Generator: "date_in_range", Min: Param(r.min), Max: Param(r.max)}, true
+			}
+		}
+	}
+	return Spec{}, false
+}
+
+// matchAny returns true if any of the sequences of words is in words
+func matchAny(words []string, sequences [][]string) bool {
+	for _, seq := range sequences {
+		for i := 0; i+len(seq) <= len(words); i++ {
+			match := true
+			for j, w := range seq {
+				if words[i+j] != w {
+					match = false
+					break
+				}
+			}
+			if match {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitWords splits a column name or a comment into lowercase words. Words are separated by any
+// non alphanumeric char or by a change to uppercase, like in firstName
+func splitWords(s string) []string {
+	words := []string{}
+	var word []rune
+	runes := []rune(s)
+	for i, c := range runes {
+		switch {
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			if len(word) > 0 {
+				words = append(words, string(word))
+			}
+			word = nil
+			continue
+		case unicode.IsUpper(c) && i > 0 && unicode.IsLower(runes[i-1]) && len(word) > 0:
+			words = append(words, string(word))
+			word = nil
+		}
+		word = append(word, unicode.ToLower(c))
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
@@ -1,40 +1,31 @@
 package getters
 
-import (
-	"fmt"
-	"math/rand"
+import "math/rand"
 
-	"github.com/icrowley/fake"
-)
-
-// RandomBinary getter
+// RandomBinary getter. Generates arbitrary bytes, including zero bytes
 type RandomBinary struct {
 	name      string
+	minSize   int64
 	maxSize   int64
+	padSize   int64
 	allowNull bool
+	rnd       *rand.Rand
 }
 
+// Value returns a []byte having between minSize and maxSize random bytes.
+// If padSize > 0 (binary(n) fields), the value is right padded with 0x00 up to padSize
+// bytes, like MySQL does when storing the value.
 func (r *RandomBinary) Value() interface{} {
-	if r.allowNull && rand.Int63n(100) < nilFrequency {
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
 		return nil
 	}
-	var s string
-	maxSize := uint64(r.maxSize)
-	if maxSize == 0 {
-		maxSize = uint64(rand.Int63n(100))
-	}
-
-	if maxSize <= 10 {
-		s = fake.FirstName()
-	} else if maxSize < 30 {
-		s = fake.FullName()
-	} else {
-		s = fake.Sentence()
+	size := r.minSize + r.rnd.Int63n(r.maxSize-r.minSize+1)
+	b := make([]byte, size)
+	r.rnd.Read(b)
+	if size < r.padSize {
+		b = append(b, make([]byte, r.padSize-size)...)
 	}
-	if len(s) > int(maxSize) {
-		s = s[:int(maxSize)]
-	}
-	return s
+	return b
 }
 
 func (r *RandomBinary) String() string {
@@ -42,7 +33,7 @@ func (r *RandomBinary) String() string {
 	if v == nil {
 		return NULL
 	}
-	return v.(string)
+	return string(v.([]byte))
 }
 
 func (r *RandomBinary) Quote() string {
@@ -50,9 +41,14 @@ func (r *RandomBinary) Quote() string {
 	if v == nil {
 		return NULL
 	}
-	return fmt.Sprintf("%q", v)
+	return QuoteBytes(v.([]byte))
 }
 
-func NewRandomBinary(name string, maxSize int64, allowNull bool) *RandomBinary {
-	return &RandomBinary{name, maxSize, allowNull}
+// NewRandomBinary returns a getter for binary, varbinary and blob fields. The values length is
+// uniformly distributed between minSize and maxSize bytes. For binary(n) fields, padSize must be n.
+func NewRandomBinary(name string, minSize, maxSize, padSize int64, allowNull bool, rnd *rand.Rand) *RandomBinary {
+	if maxSize < minSize {
+		maxSize = minSize
+	}
+	return &RandomBinary{name, minSize, maxSize, padSize, allowNull, rnd}
 }
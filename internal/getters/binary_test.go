@@ -0,0 +1,29 @@
+package getters
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestRandomBinary(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	r := NewRandomBinary("f1", 10, 20, 0, false, rnd)
+	zeros := 0
+	for i := 0; i < 100; i++ {
+		v := r.Value().([]byte)
+		tu.Assert(t, len(v) >= 10 && len(v) <= 20, "Invalid value length %d", len(v))
+		zeros += bytes.Count(v, []byte{0})
+	}
+	tu.Assert(t, zeros > 0, "There are no zero bytes in the values")
+
+	// binary(16)
+	r = NewRandomBinary("f1", 0, 16, 16, false, rnd)
+	for i := 0; i < 100; i++ {
+		v := r.Value().([]byte)
+		tu.Assert(t, len(v) == 16, "Invalid value length %d for binary(16)", len(v))
+	}
+}
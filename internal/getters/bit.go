@@ -0,0 +1,61 @@
+package getters
+
+import (
+	"math/rand"
+	"strconv"
+)
+
+// RandomBit getter. Generates values for bit(n) fields
+type RandomBit struct {
+	name      string
+	size      int64
+	allowNull bool
+	rnd       *rand.Rand
+}
+
+// Value returns an uint64 in the [0, 2^size - 1] range
+func (r *RandomBit) Value() interface{} {
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
+		return nil
+	}
+	return randomUint64(r.rnd, 1<<uint(r.size)-1)
+}
+
+// String returns the value as a big endian binary string having (size + 7) / 8 bytes.
+// Numbers cannot be used in LOAD DATA since they are read as strings and bit fields
+// take the string bytes as the value.
+func (r *RandomBit) String() string {
+	return r.Format(r.Value(), false)
+}
+
+// bitString returns v as a big endian binary string having (size + 7) / 8 bytes
+func bitString(v uint64, size int64) string {
+	b := make([]byte, (size+7)/8)
+	for i, n := len(b)-1, v; i >= 0; i, n = i-1, n>>8 {
+		b[i] = byte(n)
+	}
+	return string(b)
+}
+
+// Quote returns the value as a bit-value literal: b'0101'
+func (r *RandomBit) Quote() string {
+	return r.Format(r.Value(), true)
+}
+
+func (r *RandomBit) Format(v interface{}, quote bool) string {
+	if v == nil {
+		return NULL
+	}
+	if quote {
+		return "b'" + strconv.FormatUint(v.(uint64), 2) + "'"
+	}
+	return bitString(v.(uint64), r.size)
+}
+
+// NewRandomBit returns a getter for bit(size) fields. size must be between 1 and 64
+func NewRandomBit(name string, size int64, allowNull bool, rnd *rand.Rand) *RandomBit {
+	if size < 1 || size > 64 {
+		size = 64
+	}
+	return &RandomBit{name, size, allowNull, rnd}
+}
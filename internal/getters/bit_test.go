@@ -0,0 +1,33 @@
+package getters
+
+import (
+	"math/rand"
+	"regexp"
+	"testing"
+
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestRandomBit(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	r := NewRandomBit("f1", 3, false, rnd)
+	seen := make(map[uint64]bool)
+	for i := 0; i < 1000; i++ {
+		v := r.Value().(uint64)
+		tu.Assert(t, v <= 7, "Invalid value %d for bit(3)", v)
+		seen[v] = true
+	}
+	tu.Equals(t, 8, len(seen))
+
+	re := regexp.MustCompile(`^b'[01]{1,3}'$`)
+	for i := 0; i < 100; i++ {
+		q := r.Quote()
+		tu.Assert(t, re.MatchString(q), "Invalid bit literal %s", q)
+	}
+
+	r = NewRandomBit("f1", 64, false, rnd)
+	tu.Equals(t, 8, len(r.String()))
+	r = NewRandomBit("f1", 9, false, rnd)
+	tu.Equals(t, 2, len(r.String()))
+}
@@ -0,0 +1,153 @@
+package getters
+
+import (
+	"math"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// maxCheckTries is the number of values a Checked getter generates looking for a value accepted by its conditions
+const maxCheckTries = 100
+
+// Condition returns true if a value satisfies a condition of a CHECK constraint
+type Condition func(v interface{}) bool
+
+// Checked wraps a getter to return values satisfying the conditions of the CHECK constraints on a field.
+// Values are generated until one of them satisfies all the conditions, up to maxCheckTries times. If none
+// of them does, the last one is returned. The last value is kept so conditions on other fields can
+// compare their values with it.
+type Checked struct {
+	getter     Getter
+	conditions []Condition
+	dateOnly   bool
+	last       interface{}
+}
+
+// Value returns a value satisfying all the conditions
+func (r *Checked) Value() interface{} {
+	var v interface{}
+	for i := 0; i < maxCheckTries; i++ {
+		v = r.getter.Value()
+		if r.accept(v) {
+			break
+		}
+	}
+	r.last = v
+	return v
+}
+
+func (r *Checked) accept(v interface{}) bool {
+	for _, c := range r.conditions {
+		if !c(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Checked) String() string {
+	return r.Format(r.Value(), false)
+}
+
+func (r *Checked) Quote() string {
+	return r.Format(r.Value(), true)
+}
+
+// Format formats a value like the wrapped getter
+func (r *Checked) Format(v interface{}, quote bool) string {
+	if t, ok := v.(time.Time); ok && r.dateOnly {
+		if quote {
+			return QuoteString(t.Format("2006-01-02"))
+		}
+		return t.Format("2006-01-02")
+	}
+	return FormatValue(r.getter, v, quote)
+}
+
+// Last returns the last value returned by the getter
+func (r *Checked) Last() interface{} {
+	return r.last
+}
+
+// AddCondition adds a condition the values must satisfy
+func (r *Checked) AddCondition(c Condition) {
+	r.conditions = append(r.conditions, c)
+}
+
+// NewChecked returns a getter returning the values of getter satisfying the conditions. dateOnly must be true
+// for date fields, so time.Time values are formatted as dates.
+func NewChecked(getter Getter, dateOnly bool, conditions ...Condition) *Checked {
+	return &Checked{getter: getter, conditions: conditions, dateOnly: dateOnly}
+}
+
+// Compare returns the result of comparing a and b using op, one of =, <>, <, <=, > and >=.
+// Values are compared as times if a is a time.Time, as numbers if both are numbers or numeric strings
+// and as strings otherwise.
+func Compare(op string, a, b interface{}) bool {
+	c := compareValues(a, b)
+	switch op {
+	case "=":
+		return c == 0
+	case "<>":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+func compareValues(a, b interface{}) int {
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := toTime(b); ok {
+			switch {
+			case ta.Before(tb):
+				return -1
+			case ta.After(tb):
+				return 1
+			}
+			return 0
+		}
+	}
+	// Numbers are compared exactly, so decimal values keep all their digits
+	if ra, ok := toRat(a); ok {
+		if rb, ok := toRat(b); ok {
+			return ra.Cmp(rb)
+		}
+	}
+	return strings.Compare(valueString(a), valueString(b))
+}
+
+func toRat(v interface{}) (*big.Rat, bool) {
+	switch val := v.(type) {
+	case int64:
+		return new(big.Rat).SetInt64(val), true
+	case uint64:
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(val)), true
+	case float64:
+		if math.IsInf(val, 0) || math.IsNaN(val) {
+			return nil, false
+		}
+		return new(big.Rat).SetFloat64(val), true
+	case string:
+		return new(big.Rat).SetString(strings.TrimSpace(val))
+	}
+	return nil, false
+}
+
+func toTime(v interface{}) (time.Time, bool) {
+	switch val := v.(type) {
+	case time.Time:
+		return val, true
+	case string:
+		t, err := ParseDateTime(val)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,42 @@
+package getters
+
+import (
+	"math/rand"
+	"regexp"
+	"testing"
+	"time"
+
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestCompare(t *testing.T) {
+	tu.Assert(t, Compare("<", int64(5), "10"), "5 < '10'")
+	tu.Assert(t, Compare(">=", "2.50", "2.5"), "'2.50' >= '2.5'")
+	tu.Assert(t, Compare(">", time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), "2020-01-01"), "date comparison")
+	tu.Assert(t, Compare("<>", "b", "a"), "'b' <> 'a'")
+	tu.Assert(t, !Compare("=", uint64(1), 2.0), "1 = 2")
+}
+
+func TestChecked(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	start := NewChecked(NewRandomIntRange("start", 0, 90, false, rnd), false)
+	end := NewChecked(NewRandomFloatRange("end", 0, 100, 2, true, rnd), false,
+		func(v interface{}) bool { return v == nil || v.(float64) != 50 })
+	end.AddCondition(func(v interface{}) bool { return v == nil || Compare(">", v, start.Last()) })
+	for i := 0; i < 1000; i++ {
+		s := start.Value()
+		if e := end.Value(); e != nil {
+			tu.Assert(t, Compare(">", e, s), "%v is not greater than %v", e, s)
+		}
+	}
+
+	d := NewChecked(NewRandomDate("f1", DefaultTemporalRange("date"), false, rnd), true)
+	tu.Equals(t, 10, len(d.String()))
+
+	// Values are formatted by the wrapped getter, not in exponent notation
+	f := NewChecked(NewRandomFloatRange("f2", 0.00001, 0.00002, 6, false, rnd), false)
+	v := f.String()
+	tu.Assert(t, regexp.MustCompile(`^0\.0000[12]\d$`).MatchString(v), "invalid value %s", v)
+	tu.Assert(t, Compare("=", "12345678901234567890.0000000001", "12345678901234567890.0000000001"), "exact equality")
+	tu.Assert(t, Compare("<", "12345678901234567890.0000000001", "12345678901234567890.0000000002"), "exact comparison")
+}
@@ -1,8 +1,6 @@
 package getters
 
-import "fmt"
-
-// Constant Getter. Used for debugging
+// Constant Getter. Always returns the same value
 type Constant struct {
 	value interface{}
 }
@@ -12,11 +10,11 @@ func (r *Constant) Value() interface{} {
 }
 
 func (r *Constant) String() string {
-	return fmt.Sprintf("%s", r.Value())
+	return valueString(r.Value())
 }
 
 func (r *Constant) Quote() string {
-	return fmt.Sprintf("%q", r.Value())
+	return QuoteValue(r.Value())
 }
 
 func NewConstant(value interface{}) *Constant {
@@ -6,63 +6,113 @@ import (
 	"time"
 )
 
+// RandomDate getter. Generates date values in a range
 type RandomDate struct {
 	name      string
+	r         TemporalRange
 	allowNull bool
+	rnd       *rand.Rand
 }
 
+// Value returns a random time.Time in the range, truncated to the day
 func (r *RandomDate) Value() interface{} {
-	var randomSeconds time.Duration
-	for i := 0; i < 10 && randomSeconds != 0; i++ {
-		randomSeconds = time.Duration(rand.Int63n(int64(oneYear)) + rand.Int63n(100))
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
+		return nil
 	}
-	d := time.Now().Add(-1 * randomSeconds)
-	return d
+	return r.r.random(r.rnd, 24*time.Hour)
 }
 
 func (r *RandomDate) String() string {
-	d := r.Value().(time.Time)
-	return d.Format("2006-01-02 15:03:04")
+	return r.Format(r.Value(), false)
 }
 
 func (r *RandomDate) Quote() string {
-	d := r.Value().(time.Time)
-	return fmt.Sprintf("'%s'", d.Format("2006-01-02 15:03:04"))
+	return r.Format(r.Value(), true)
 }
 
-func NewRandomDate(name string, allowNull bool) *RandomDate {
-	return &RandomDate{name, allowNull}
+func (r *RandomDate) Format(v interface{}, quote bool) string {
+	if v == nil {
+		return NULL
+	}
+	if quote {
+		return fmt.Sprintf("'%s'", v.(time.Time).Format("2006-01-02"))
+	}
+	return v.(time.Time).Format("2006-01-02")
 }
 
+// NewRandomDate returns a new random date getter. Use DefaultTemporalRange("date") for the full
+// range of the date type.
+func NewRandomDate(name string, r TemporalRange, allowNull bool, rnd *rand.Rand) *RandomDate {
+	return &RandomDate{name, r, allowNull, rnd}
+}
+
+// RandomDateInRange getter. Generates date, datetime and timestamp values between explicit bounds
 type RandomDateInRange struct {
 	name      string
-	min       string
-	max       string
+	dataType  string
+	precision int64
+	r         TemporalRange
 	allowNull bool
+	rnd       *rand.Rand
 }
 
+// Value returns a random time.Time in the range, truncated to the day for date fields
+// or to the precision for datetime and timestamp fields
 func (r *RandomDateInRange) Value() interface{} {
-	rand.Seed(time.Now().UnixNano())
-	var randomSeconds int64
-	randomSeconds = rand.Int63n(oneYear) + rand.Int63n(100)
-	d := time.Now().Add(-1 * time.Duration(randomSeconds) * time.Second)
-	return d
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
+		return nil
+	}
+	if r.dataType == "date" {
+		return r.r.random(r.rnd, 24*time.Hour)
+	}
+	return r.r.random(r.rnd, fractionUnit(r.precision))
 }
 
 func (r *RandomDateInRange) String() string {
-	d := r.Value().(time.Time)
-	return d.Format("2006-01-02 15:03:04")
+	return r.Format(r.Value(), false)
 }
 
 func (r *RandomDateInRange) Quote() string {
-	d := r.Value().(time.Time)
-	return fmt.Sprintf("'%s'", d.Format("2006-01-02 15:03:04"))
+	return r.Format(r.Value(), true)
 }
 
-func NewRandomDateInRange(name string, min, max string, allowNull bool) *RandomDateInRange {
+func (r *RandomDateInRange) Format(v interface{}, quote bool) string {
+	if v == nil {
+		return NULL
+	}
+	if quote {
+		return QuoteString(r.format(v.(time.Time)))
+	}
+	return r.format(v.(time.Time))
+}
+
+func (r *RandomDateInRange) format(t time.Time) string {
+	if r.dataType == "date" {
+		return t.Format("2006-01-02")
+	}
+	return formatDateTime(t, r.precision)
+}
+
+// NewRandomDateInRange returns a new random date getter for a date, datetime or timestamp field, generating
+// values between min and max. Bounds are parsed using ParseDateTime so they can be relative to the reference
+// time, like -90d. An empty min means 1 year before the reference time and an empty max means the reference time.
+func NewRandomDateInRange(name, dataType string, precision int64, min, max string, allowNull bool,
+	rnd *rand.Rand) (*RandomDateInRange, error) {
+	r, err := ParseDateInRangeBounds(dataType, min, max)
+	if err != nil {
+		return nil, err
+	}
+	return &RandomDateInRange{name, dataType, clampPrecision(precision), r, allowNull, rnd}, nil
+}
+
+// ParseDateInRangeBounds returns the range between min and max for a date, datetime or timestamp field, with
+// the defaults of NewRandomDateInRange for empty bounds
+func ParseDateInRangeBounds(dataType, min, max string) (TemporalRange, error) {
 	if min == "" {
-		t := time.Now().Add(-1 * time.Duration(oneYear) * time.Second)
-		min = t.Format("2006-01-02")
+		min = "-1y"
+	}
+	if max == "" {
+		max = "now"
 	}
-	return &RandomDateInRange{name, min, max, allowNull}
+	return ParseTemporalBounds(dataType, min, max)
 }
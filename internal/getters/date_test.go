@@ -0,0 +1,52 @@
+package getters
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestRandomDateInRange(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	SetReferenceTime(time.Date(2020, 6, 15, 12, 0, 0, 0, time.UTC))
+	defer SetReferenceTime(time.Now())
+
+	tests := []struct {
+		dataType  string
+		precision int64
+		min, max  string
+		wantMin   string
+		wantMax   string
+	}{
+		{"date", 0, "2019-01-01", "2019-01-31", "2019-01-01", "2019-01-31"},
+		{"date", 0, "-90d", "now", "2020-03-17", "2020-06-15"},
+		{"date", 0, "", "", "2019-06-15", "2020-06-15"},
+		{"datetime", 0, "2019-01-01 10:00:00", "2019-01-01 10:00:05", "2019-01-01 10:00:00", "2019-01-01 10:00:05"},
+		{"datetime", 3, "-1h", "+1h", "2020-06-15 11:00:00.000", "2020-06-15 13:00:00.000"},
+		{"timestamp", 0, "-2w", "-1w", "2020-06-01 12:00:00", "2020-06-08 12:00:00"},
+	}
+	for _, test := range tests {
+		r, err := NewRandomDateInRange("f1", test.dataType, test.precision, test.min, test.max, false, rnd)
+		tu.Ok(t, err)
+		for i := 0; i < 1000; i++ {
+			v := r.String()
+			tu.Assert(t, len(v) == len(test.wantMin), "Invalid %s value %s", test.dataType, v)
+			tu.Assert(t, v >= test.wantMin && v <= test.wantMax, "%s value %s out of range %s ~ %s",
+				test.dataType, v, test.wantMin, test.wantMax)
+		}
+	}
+}
+
+func TestRandomDateInRangeErrors(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	_, err := NewRandomDateInRange("f1", "date", 0, "2019-12-31", "2019-01-01", false, rnd)
+	tu.NotOk(t, err)
+	_, err = NewRandomDateInRange("f1", "timestamp", 0, "1960-01-01", "1970-01-02", false, rnd)
+	tu.NotOk(t, err)
+	_, err = NewRandomDateInRange("f1", "date", 0, "-90x", "now", false, rnd)
+	tu.NotOk(t, err)
+	_, err = NewRandomDateTimeInRange("f1", "yesterday", "", false, rnd)
+	tu.NotOk(t, err)
+}
@@ -1,46 +1,87 @@
 package getters
 
 import (
-	"fmt"
 	"math/rand"
 	"time"
 )
 
-type RandomDateTimeInRange struct {
-	min       string
-	max       string
+// NewRandomDateTimeInRange returns a new random datetime getter generating values between min and max.
+// See NewRandomDateInRange.
+func NewRandomDateTimeInRange(name string, min, max string, allowNull bool, rnd *rand.Rand) (*RandomDateInRange, error) {
+	return NewRandomDateInRange(name, "datetime", 0, min, max, allowNull, rnd)
+}
+
+// RandomDateTime getter. Generates datetime and timestamp values having up to precision fractional digits
+type RandomDateTime struct {
+	name      string
+	precision int64
+	r         TemporalRange
 	allowNull bool
+	rnd       *rand.Rand
 }
 
-// Value returns a random time.Time in the range specified by the New method
-func (r *RandomDateTimeInRange) Value() interface{} {
-	rand.Seed(time.Now().UnixNano())
-	randomSeconds := rand.Int63n(oneYear)
-	d := time.Now().Add(-1 * time.Duration(randomSeconds) * time.Second)
-	return d
+// Value returns a random time.Time in the range, truncated to the precision
+func (r *RandomDateTime) Value() interface{} {
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
+		return nil
+	}
+	return r.r.random(r.rnd, fractionUnit(r.precision))
+}
+
+func (r *RandomDateTime) String() string {
+	return r.Format(r.Value(), false)
+}
+
+func (r *RandomDateTime) Quote() string {
+	return r.Format(r.Value(), true)
+}
+
+func (r *RandomDateTime) Format(v interface{}, quote bool) string {
+	if v == nil {
+		return NULL
+	}
+	if quote {
+		return QuoteString(formatDateTime(v.(time.Time), r.precision))
+	}
+	return formatDateTime(v.(time.Time), r.precision)
 }
 
-func (r *RandomDateTimeInRange) String() string {
-	d := r.Value().(time.Time)
-	return d.Format("2006-01-02 15:03:04")
+// NewRandomDateTime returns a new random datetime or timestamp getter. precision is the number of
+// fractional digits, as in datetime(6), between 0 and 6. Use DefaultTemporalRange("datetime") or
+// DefaultTemporalRange("timestamp") for the full range of the type.
+func NewRandomDateTime(name string, precision int64, r TemporalRange, allowNull bool, rnd *rand.Rand) *RandomDateTime {
+	return &RandomDateTime{name, clampPrecision(precision), r, allowNull, rnd}
 }
 
-// Quote returns the value quoted for MySQL
-func (r *RandomDateTimeInRange) Quote() string {
-	d := r.Value().(time.Time)
-	return fmt.Sprintf("'%s'", d.Format("2006-01-02 15:03:04"))
+// formatDateTime returns t formatted as YYYY-MM-DD HH:MM:SS having precision fractional digits
+func formatDateTime(t time.Time, precision int64) string {
+	return t.Format("2006-01-02 15:04:05" + fractionLayout(precision))
 }
 
-// NewRandomDateTimeInRange returns a new random date in the specified range
-func NewRandomDateTimeInRange(name string, min, max string, allowNull bool) *RandomDateInRange {
-	if min == "" {
-		t := time.Now().Add(-1 * time.Duration(oneYear) * time.Second)
-		min = t.Format("2006-01-02")
+// fractionLayout returns the layout for precision fractional digits
+func fractionLayout(precision int64) string {
+	if precision <= 0 {
+		return ""
 	}
-	return &RandomDateInRange{name, min, max, allowNull}
+	return ".000000"[:precision+1]
 }
 
-// NewRandomDateTime returns a new random datetime between Now() and Now() - 1 year
-func NewRandomDateTime(name string, allowNull bool) *RandomDateInRange {
-	return &RandomDateInRange{name, "", "", allowNull}
+// fractionUnit returns the smallest duration that can be represented with precision fractional digits
+func fractionUnit(precision int64) time.Duration {
+	d := time.Second
+	for i := int64(0); i < precision; i++ {
+		d /= 10
+	}
+	return d
+}
+
+// clampPrecision returns the fractional seconds precision in the valid [0, 6] range
+func clampPrecision(precision int64) int64 {
+	if precision < 0 {
+		return 0
+	}
+	if precision > 6 {
+		return 6
+	}
+	return precision
 }
@@ -0,0 +1,46 @@
+package getters
+
+import (
+	"math/rand"
+	"regexp"
+	"testing"
+
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestRandomDateTimePrecision(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	tests := []struct {
+		precision int64
+		re        *regexp.Regexp
+	}{
+		{0, regexp.MustCompile(`^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}$`)},
+		{3, regexp.MustCompile(`^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\.\d{3}$`)},
+		{6, regexp.MustCompile(`^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\.\d{6}$`)},
+	}
+	for _, test := range tests {
+		r := NewRandomDateTime("f1", test.precision, DefaultTemporalRange("datetime"), false, rnd)
+		for i := 0; i < 100; i++ {
+			v := r.String()
+			tu.Assert(t, test.re.MatchString(v), "Invalid datetime(%d) value %s", test.precision, v)
+		}
+	}
+}
+
+func TestRandomTimePrecision(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	min, max := DefaultTimeRange()
+	r := NewRandomTime(0, min, max, false, rnd)
+	re := regexp.MustCompile(`^-?\d{2,3}:\d{2}:\d{2}$`)
+	for i := 0; i < 100; i++ {
+		v := r.String()
+		tu.Assert(t, re.MatchString(v), "Invalid time value %s", v)
+	}
+
+	r = NewRandomTime(4, min, max, false, rnd)
+	re = regexp.MustCompile(`^-?\d{2,3}:\d{2}:\d{2}\.\d{4}$`)
+	for i := 0; i < 100; i++ {
+		v := r.String()
+		tu.Assert(t, re.MatchString(v), "Invalid time(4) value %s", v)
+	}
+}
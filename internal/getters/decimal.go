@@ -1,34 +1,258 @@
 package getters
 
 import (
-	"fmt"
+	"math"
+	"math/big"
 	"math/rand"
+	"strconv"
+	"strings"
 )
 
 // RandomDecimal holds unexported data for decimal values
 type RandomDecimal struct {
 	name      string
-	size      int64
+	precision int64
+	scale     int64
+	unsigned  bool
 	allowNull bool
+	rnd       *rand.Rand
 }
 
+// Value returns a random decimal(precision, scale) value as a string.
+// Values are built digit by digit, so they fit exactly into the field even for 65 digits decimals.
 func (r *RandomDecimal) Value() interface{} {
-	size := r.size
-	if size > 10 {
-		size = 10
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
+		return nil
 	}
-	f := rand.Float64() * float64(rand.Int63n(int64(size)))
-	return f
+	var b strings.Builder
+	if !r.unsigned && r.rnd.Int63n(2) == 0 {
+		b.WriteByte('-')
+	}
+
+	intPart := r.digits(r.precision - r.scale)
+	intPart = strings.TrimLeft(intPart, "0")
+	if intPart == "" {
+		intPart = "0"
+	}
+	b.WriteString(intPart)
+
+	if r.scale > 0 {
+		b.WriteByte('.')
+		b.WriteString(r.digits(r.scale))
+	}
+
+	v := b.String()
+	if strings.Trim(v, "-0.") == "" {
+		v = strings.TrimPrefix(v, "-")
+	}
+	return v
+}
+
+func (r *RandomDecimal) digits(n int64) string {
+	buf := make([]byte, n)
+	for i := range buf {
+		buf[i] = byte('0' + r.rnd.Int63n(10))
+	}
+	return string(buf)
 }
 
 func (r *RandomDecimal) String() string {
-	return fmt.Sprintf("%0f", r.Value())
+	return r.Format(r.Value(), false)
 }
 
 func (r *RandomDecimal) Quote() string {
 	return r.String()
 }
 
-func NewRandomDecimal(name string, size int64, allowNull bool) *RandomDecimal {
-	return &RandomDecimal{name, size, allowNull}
+// Format returns the value unquoted, since decimal values are numbers
+func (r *RandomDecimal) Format(v interface{}, quote bool) string {
+	if v == nil {
+		return NULL
+	}
+	return v.(string)
+}
+
+// NewRandomDecimal returns a getter for decimal(precision, scale) fields
+func NewRandomDecimal(name string, precision, scale int64, unsigned, allowNull bool, rnd *rand.Rand) *RandomDecimal {
+	if precision < scale {
+		precision = scale
+	}
+	return &RandomDecimal{name, precision, scale, unsigned, allowNull, rnd}
+}
+
+const (
+	defaultFloatMaxValue  = 1e8
+	defaultDoubleMaxValue = 1e10
+)
+
+// RandomFloat holds unexported data for float and double values
+type RandomFloat struct {
+	name      string
+	bitSize   int
+	maxValue  float64
+	scale     int64
+	unsigned  bool
+	allowNull bool
+	rnd       *rand.Rand
+}
+
+// Value returns a random float64. For float fields, the value has float (32 bits) precision.
+func (r *RandomFloat) Value() interface{} {
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
+		return nil
+	}
+	v := r.rnd.Float64() * r.maxValue
+	if !r.unsigned && r.rnd.Int63n(2) == 0 {
+		v = -v
+	}
+	if r.bitSize == 32 {
+		return float64(float32(v))
+	}
+	return v
+}
+
+func (r *RandomFloat) String() string {
+	return r.Format(r.Value(), false)
+}
+
+func (r *RandomFloat) Format(v interface{}, quote bool) string {
+	if v == nil {
+		return NULL
+	}
+	if r.scale >= 0 {
+		return strconv.FormatFloat(v.(float64), 'f', int(r.scale), r.bitSize)
+	}
+	return strconv.FormatFloat(v.(float64), 'f', -1, r.bitSize)
+}
+
+func (r *RandomFloat) Quote() string {
+	return r.String()
+}
+
+// NewRandomFloat returns a getter for float (bitSize 32) and double (bitSize 64) fields.
+// For float(M,D) and double(M,D) fields, precision and scale are M and D, so the values are in the
+// -(10^(M-D) - 10^-D) ~ 10^(M-D) - 10^-D range. If precision is 0, the values are in the
+// -1e8 ~ 1e8 range for floats and -1e10 ~ 1e10 for doubles.
+func NewRandomFloat(name string, bitSize int, precision, scale int64, unsigned, allowNull bool, rnd *rand.Rand) *RandomFloat {
+	maxValue := defaultDoubleMaxValue
+	if bitSize == 32 {
+		maxValue = defaultFloatMaxValue
+	}
+	if precision > 0 {
+		maxValue = math.Pow10(int(precision-scale)) - math.Pow10(-int(scale))
+	} else {
+		scale = -1
+	}
+	return &RandomFloat{name, bitSize, maxValue, scale, unsigned, allowNull, rnd}
+}
+
+// RandomFloatRange returns random float64 values in the [min, max] range, rounded to scale decimals.
+// Used for decimal, float and double fields having CHECK constraints on their values.
+type RandomFloatRange struct {
+	name      string
+	min       float64
+	max       float64
+	scale     int64
+	allowNull bool
+	rnd       *rand.Rand
+}
+
+// Value returns a random float64 in the range
+func (r *RandomFloatRange) Value() interface{} {
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
+		return nil
+	}
+	v := r.min + r.rnd.Float64()*(r.max-r.min)
+	if r.scale >= 0 {
+		p := math.Pow10(int(r.scale))
+		v = math.Round(v*p) / p
+	}
+	return math.Max(r.min, math.Min(r.max, v))
+}
+
+func (r *RandomFloatRange) String() string {
+	return r.Format(r.Value(), false)
+}
+
+func (r *RandomFloatRange) Format(v interface{}, quote bool) string {
+	if v == nil {
+		return NULL
+	}
+	return strconv.FormatFloat(v.(float64), 'f', int(r.scale), 64)
+}
+
+func (r *RandomFloatRange) Quote() string {
+	return r.String()
+}
+
+// NewRandomFloatRange returns a getter for values in the [min, max] range having scale decimals.
+// If scale is negative, values are not rounded.
+func NewRandomFloatRange(name string, min, max float64, scale int64, allowNull bool, rnd *rand.Rand) *RandomFloatRange {
+	if scale < 0 {
+		scale = -1
+	}
+	return &RandomFloatRange{name, min, max, scale, allowNull, rnd}
+}
+
+// RandomDecimalRange returns random decimal values in a range, as strings having scale decimals.
+// Used for decimal fields having CHECK constraints on their values, so the bounds keep all their digits.
+type RandomDecimalRange struct {
+	name      string
+	min       *big.Int
+	size      *big.Int
+	scale     int64
+	allowNull bool
+	rnd       *rand.Rand
+}
+
+// Value returns a random decimal in the range as a string
+func (r *RandomDecimalRange) Value() interface{} {
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
+		return nil
+	}
+	v := new(big.Int).Rand(r.rnd, r.size)
+	return formatScaled(v.Add(v, r.min), r.scale)
+}
+
+func (r *RandomDecimalRange) String() string {
+	return r.Format(r.Value(), false)
+}
+
+func (r *RandomDecimalRange) Quote() string {
+	return r.String()
+}
+
+// Format returns the value unquoted, since decimal values are numbers
+func (r *RandomDecimalRange) Format(v interface{}, quote bool) string {
+	if v == nil {
+		return NULL
+	}
+	return v.(string)
+}
+
+// NewRandomDecimalRange returns a getter for decimal values in a range having scale decimals. min and max are
+// the bounds of the range multiplied by 10^scale, so min = 1234 and scale = 2 is 12.34. Both are included.
+func NewRandomDecimalRange(name string, min, max *big.Int, scale int64, allowNull bool, rnd *rand.Rand) *RandomDecimalRange {
+	size := new(big.Int).Sub(max, min)
+	size.Add(size, big.NewInt(1))
+	return &RandomDecimalRange{name, new(big.Int).Set(min), size, scale, allowNull, rnd}
+}
+
+// formatScaled returns v / 10^scale with scale decimals
+func formatScaled(v *big.Int, scale int64) string {
+	digits := new(big.Int).Abs(v).String()
+	if scale <= 0 {
+		if v.Sign() < 0 {
+			return "-" + digits
+		}
+		return digits
+	}
+	if int64(len(digits)) <= scale {
+		digits = strings.Repeat("0", int(scale)-len(digits)+1) + digits
+	}
+	s := digits[:int64(len(digits))-scale] + "." + digits[int64(len(digits))-scale:]
+	if v.Sign() < 0 {
+		return "-" + s
+	}
+	return s
 }
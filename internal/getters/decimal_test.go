@@ -0,0 +1,68 @@
+package getters
+
+import (
+	"math/big"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"testing"
+
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestRandomDecimal(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	tests := []struct {
+		precision, scale int64
+		unsigned         bool
+		re               *regexp.Regexp
+	}{
+		{4, 2, false, regexp.MustCompile(`^-?\d{1,2}\.\d{2}$`)},
+		{10, 0, true, regexp.MustCompile(`^\d{1,10}$`)},
+		{65, 30, false, regexp.MustCompile(`^-?\d{1,35}\.\d{30}$`)},
+		{5, 5, true, regexp.MustCompile(`^0\.\d{5}$`)},
+	}
+
+	for _, test := range tests {
+		r := NewRandomDecimal("f1", test.precision, test.scale, test.unsigned, false, rnd)
+		for i := 0; i < 100; i++ {
+			v := r.String()
+			tu.Assert(t, test.re.MatchString(v), "Invalid value %q for decimal(%d,%d)", v, test.precision, test.scale)
+			f, err := strconv.ParseFloat(v, 64)
+			tu.Ok(t, err)
+			tu.Assert(t, f != 0 || v[0] != '-', "Invalid negative zero %q", v)
+		}
+	}
+}
+
+func TestRandomDecimalRange(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	r := NewRandomDecimalRange("f1", big.NewInt(-3), big.NewInt(2), 3, false, rnd)
+	want := map[string]bool{"-0.003": true, "-0.002": true, "-0.001": true, "0.000": true, "0.001": true, "0.002": true}
+	seen := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		v := r.String()
+		tu.Assert(t, want[v], "Invalid value %q", v)
+		seen[v] = true
+	}
+	tu.Equals(t, want, seen)
+	tu.Equals(t, "-12.30", formatScaled(big.NewInt(-1230), 2))
+	tu.Equals(t, "7", formatScaled(big.NewInt(7), 0))
+}
+
+func TestRandomFloat(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	r := NewRandomFloat("f1", 32, 5, 3, false, false, rnd)
+	re := regexp.MustCompile(`^-?\d{1,2}\.\d{3}$`)
+	for i := 0; i < 100; i++ {
+		v := r.String()
+		tu.Assert(t, re.MatchString(v), "Invalid value %q for float(5,3)", v)
+	}
+
+	r = NewRandomFloat("f1", 64, 0, 0, true, false, rnd)
+	for i := 0; i < 100; i++ {
+		v, err := strconv.ParseFloat(r.String(), 64)
+		tu.Ok(t, err)
+		tu.Assert(t, v >= 0 && v <= defaultDoubleMaxValue, "Invalid value %v for unsigned double", v)
+	}
+}
@@ -0,0 +1,294 @@
+package getters
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// maxDistributionTries is the number of positions the normal and exponential distributions generate
+// looking for one inside the range before clamping it
+const maxDistributionTries = 10
+
+// Distribution chooses positions in the [0, max] range. Distributed getters map the positions to the values
+// in their ranges or lists, so the same distributions can be used for numbers, dates and lists of values.
+type Distribution interface {
+	Position(rnd *rand.Rand, max uint64) uint64
+}
+
+// Uniform distribution. All the positions have the same probability
+type Uniform struct{}
+
+func (Uniform) Position(rnd *rand.Rand, max uint64) uint64 {
+	return randomUint64(rnd, max)
+}
+
+// NewUniform returns a uniform distribution
+func NewUniform() Uniform {
+	return Uniform{}
+}
+
+// Zipf distribution. The probability of the position k is proportional to 1 / (k + 1)^s, so the first
+// positions are the hot keys and most of the others are rarely chosen
+type Zipf struct {
+	s       float64
+	reverse bool
+	zipf    *rand.Zipf
+	rnd     *rand.Rand
+	max     uint64
+}
+
+func (d *Zipf) Position(rnd *rand.Rand, max uint64) uint64 {
+	if d.zipf == nil || d.rnd != rnd || d.max != max {
+		d.zipf, d.rnd, d.max = rand.NewZipf(rnd, d.s, 1, max), rnd, max
+	}
+	pos := d.zipf.Uint64()
+	if d.reverse {
+		return max - pos
+	}
+	return pos
+}
+
+// NewZipf returns a Zipf distribution. s must be greater than 1; higher values concentrate more values
+// in the first positions. If reverse is true, the hot keys are the last positions.
+func NewZipf(s float64, reverse bool) *Zipf {
+	return &Zipf{s: s, reverse: reverse}
+}
+
+// Normal distribution, truncated to the range
+type Normal struct {
+	mean   float64
+	stddev float64
+}
+
+func (d Normal) Position(rnd *rand.Rand, max uint64) uint64 {
+	var v float64
+	for i := 0; i < maxDistributionTries; i++ {
+		v = math.Round(rnd.NormFloat64()*d.stddev + d.mean)
+		if v >= 0 && v <= float64(max) {
+			break
+		}
+	}
+	return clampPosition(v, max)
+}
+
+// NewNormal returns a normal distribution having its mean and standard deviation in positions
+func NewNormal(mean, stddev float64) Normal {
+	return Normal{mean, stddev}
+}
+
+// Exponential distribution, truncated to the range. The probability decays exponentially from the
+// first position
+type Exponential struct {
+	rate    float64
+	reverse bool
+}
+
+func (d Exponential) Position(rnd *rand.Rand, max uint64) uint64 {
+	var v float64
+	for i := 0; i < maxDistributionTries; i++ {
+		if v = rnd.ExpFloat64() / d.rate; v < 1 {
+			break
+		}
+	}
+	pos := clampPosition(math.Floor(v*(float64(max)+1)), max)
+	if d.reverse {
+		return max - pos
+	}
+	return pos
+}
+
+// NewExponential returns an exponential distribution. rate is the decay rate over the whole range: the
+// probability of the last position is e^-rate times the probability of the first one. If reverse is true,
+// the probability decays from the last position.
+func NewExponential(rate float64, reverse bool) Exponential {
+	return Exponential{rate, reverse}
+}
+
+// Weights distribution. The probability of each position is proportional to its weight. Positions
+// without a weight are never chosen
+type Weights struct {
+	cumulative []float64
+}
+
+func (d Weights) Position(rnd *rand.Rand, max uint64) uint64 {
+	cumulative := d.cumulative
+	if max < uint64(len(cumulative)-1) {
+		cumulative = cumulative[:max+1]
+	}
+	x := rnd.Float64() * cumulative[len(cumulative)-1]
+	i := sort.Search(len(cumulative), func(i int) bool { return cumulative[i] > x })
+	if i == len(cumulative) {
+		i--
+	}
+	return uint64(i)
+}
+
+// NewWeights returns a distribution choosing the positions with the probabilities given by weights, which
+// don't need to add up to 1. At least one of the weights must be positive.
+func NewWeights(weights []float64) Weights {
+	d := Weights{cumulative: make([]float64, len(weights))}
+	total := 0.0
+	for i, w := range weights {
+		if w > 0 {
+			total += w
+		}
+		d.cumulative[i] = total
+	}
+	return d
+}
+
+func clampPosition(v float64, max uint64) uint64 {
+	switch {
+	case v <= 0 || math.IsNaN(v):
+		return 0
+	case v >= float64(max):
+		return max
+	}
+	return uint64(v)
+}
+
+// DistributedInt getter. Generates integers between min and max following a distribution
+type DistributedInt struct {
+	name      string
+	min       uint64
+	max       uint64
+	unsigned  bool
+	dist      Distribution
+	allowNull bool
+	rnd       *rand.Rand
+}
+
+// Value returns an int64, or an uint64 for unsigned getters
+func (r *DistributedInt) Value() interface{} {
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
+		return nil
+	}
+	v := r.min + r.dist.Position(r.rnd, r.max)
+	if r.unsigned {
+		return v
+	}
+	return int64(v)
+}
+
+func (r *DistributedInt) String() string {
+	v := r.Value()
+	if v == nil {
+		return NULL
+	}
+	return valueString(v)
+}
+
+func (r *DistributedInt) Quote() string {
+	return r.String()
+}
+
+// NewDistributedIntRange returns a getter for signed integers between min and max, following dist.
+// The position 0 of the distribution is min.
+func NewDistributedIntRange(name string, min, max int64, dist Distribution, allowNull bool, rnd *rand.Rand) *DistributedInt {
+	return &DistributedInt{name, uint64(min), uint64(max) - uint64(min), false, dist, allowNull, rnd}
+}
+
+// NewDistributedUintRange returns a getter for unsigned integers between min and max, following dist.
+// The position 0 of the distribution is min.
+func NewDistributedUintRange(name string, min, max uint64, dist Distribution, allowNull bool, rnd *rand.Rand) *DistributedInt {
+	return &DistributedInt{name, min, max - min, true, dist, allowNull, rnd}
+}
+
+// DistributedDate getter. Generates date, datetime and timestamp values between explicit bounds
+// following a distribution
+type DistributedDate struct {
+	*RandomDateInRange
+	dist Distribution
+}
+
+// Value returns a time.Time in the range. The positions of the distribution are the seconds since the
+// start of the range; the fractional seconds are uniformly distributed.
+func (r *DistributedDate) Value() interface{} {
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
+		return nil
+	}
+	min, max := r.r.Min, r.r.Max
+	seconds := min.Unix() + int64(r.dist.Position(r.rnd, uint64(max.Unix()-min.Unix())))
+	// The fraction of the last second would be after max
+	var nanos int64
+	if seconds < max.Unix() {
+		nanos = r.rnd.Int63n(int64(time.Second))
+	}
+	t := time.Unix(seconds, nanos).UTC()
+	if r.dataType == "date" {
+		t = t.Truncate(24 * time.Hour)
+	} else {
+		t = t.Truncate(fractionUnit(r.precision))
+	}
+	if t.Before(min) {
+		return min
+	}
+	return t
+}
+
+func (r *DistributedDate) String() string {
+	return r.Format(r.Value(), false)
+}
+
+func (r *DistributedDate) Quote() string {
+	return r.Format(r.Value(), true)
+}
+
+func (r *DistributedDate) Format(v interface{}, quote bool) string {
+	if v == nil {
+		return NULL
+	}
+	if quote {
+		return QuoteString(r.format(v.(time.Time)))
+	}
+	return r.format(v.(time.Time))
+}
+
+// NewDistributedDateInRange returns a getter for a date, datetime or timestamp field generating values
+// in the range following dist. Use ParseDateInRangeBounds to get the range of the date_in_range generator.
+func NewDistributedDateInRange(name, dataType string, precision int64, r TemporalRange, dist Distribution,
+	allowNull bool, rnd *rand.Rand) *DistributedDate {
+	return &DistributedDate{&RandomDateInRange{name, dataType, clampPrecision(precision), r, allowNull, rnd}, dist}
+}
+
+// DistributedSample getter. Returns values from a list, like the members of an enum or the samples
+// of a foreign key, chosen following a distribution. The first values of the list are the first
+// positions of the distribution.
+type DistributedSample struct {
+	name      string
+	samples   []interface{}
+	dist      Distribution
+	allowNull bool
+	rnd       *rand.Rand
+}
+
+func (r *DistributedSample) Value() interface{} {
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
+		return nil
+	}
+	return r.samples[r.dist.Position(r.rnd, uint64(len(r.samples)-1))]
+}
+
+func (r *DistributedSample) String() string {
+	v := r.Value()
+	if v == nil {
+		return NULL
+	}
+	return valueString(v)
+}
+
+func (r *DistributedSample) Quote() string {
+	v := r.Value()
+	if v == nil {
+		return NULL
+	}
+	return QuoteValue(v)
+}
+
+// NewDistributedSample returns a getter choosing values from samples following dist
+func NewDistributedSample(name string, samples []interface{}, dist Distribution, allowNull bool,
+	rnd *rand.Rand) *DistributedSample {
+	return &DistributedSample{name, samples, dist, allowNull, rnd}
+}
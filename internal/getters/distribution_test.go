@@ -0,0 +1,101 @@
+package getters
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestDistributions(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	counts := func(d Distribution, max uint64) []int {
+		c := make([]int, max+1)
+		for i := 0; i < 10000; i++ {
+			pos := d.Position(rnd, max)
+			tu.Assert(t, pos <= max, "Position %d out of the [0, %d] range", pos, max)
+			c[pos]++
+		}
+		return c
+	}
+
+	c := counts(NewZipf(1.5, false), 99)
+	tu.Assert(t, c[0] > 3000, "Invalid frequency %d for the first zipf position", c[0])
+	tu.Assert(t, c[0] > c[1] && c[1] > c[2] && c[2] > c[10], "Zipf frequencies must decrease: %v", c[:11])
+	c = counts(NewZipf(1.5, true), 99)
+	tu.Assert(t, c[99] > 3000, "Invalid frequency %d for the last reversed zipf position", c[99])
+
+	c = counts(NewNormal(50, 5), 99)
+	within := 0
+	for i := 45; i <= 55; i++ {
+		within += c[i]
+	}
+	tu.Assert(t, within > 6500 && within < 8000, "Invalid frequency %d within 1 stddev of the mean", within)
+
+	c = counts(NewExponential(5, false), 9)
+	tu.Assert(t, c[0] > 3500 && c[0] < 4500, "Invalid frequency %d for the first exponential position", c[0])
+	tu.Assert(t, c[0] > c[4] && c[4] > c[9], "Exponential frequencies must decrease: %v", c)
+	c = counts(NewExponential(5, true), 9)
+	tu.Assert(t, c[9] > c[5] && c[5] > c[0], "Reversed exponential frequencies must increase: %v", c)
+
+	c = counts(NewWeights([]float64{3, 0, 1}), 2)
+	tu.Equals(t, 0, c[1])
+	tu.Assert(t, c[0] > 7000 && c[0] < 8000, "Invalid frequency %d for the first weighted position", c[0])
+
+	// Positions without a weight are never chosen, and weights beyond the range are ignored
+	tu.Equals(t, []int{10000}, counts(NewWeights([]float64{1, 2}), 0))
+
+	// The full uint64 range
+	for _, d := range []Distribution{NewUniform(), NewZipf(2, true), NewNormal(1e19, 1e18), NewExponential(1, false),
+		NewWeights([]float64{1})} {
+		d.Position(rnd, math.MaxUint64)
+	}
+}
+
+func TestDistributedGetters(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	g := NewDistributedIntRange("f", -10, 10, NewZipf(2, false), false, rnd)
+	counts := make(map[int64]int)
+	for i := 0; i < 1000; i++ {
+		v := g.Value().(int64)
+		tu.Assert(t, v >= -10 && v <= 10, "Value %d out of range", v)
+		counts[v]++
+	}
+	tu.Assert(t, counts[-10] > 500, "Invalid frequency %d for the hot key", counts[-10])
+
+	g = NewDistributedUintRange("f", 0, math.MaxUint64, NewZipf(2, true), false, rnd)
+	tu.Assert(t, g.Value().(uint64) > math.MaxUint64-1000, "Invalid hot key")
+	g = NewDistributedIntRange("f", math.MinInt64, math.MaxInt64, NewExponential(10, false), false, rnd)
+	tu.Assert(t, g.Value().(int64) < 0, "Invalid value")
+
+	r, err := ParseDateInRangeBounds("datetime", "2020-01-01", "2020-12-31")
+	tu.Ok(t, err)
+	d := NewDistributedDateInRange("f", "datetime", 3, r, NewExponential(40, true), false, rnd)
+	for i := 0; i < 100; i++ {
+		v := d.Value().(time.Time)
+		tu.Assert(t, !v.Before(r.Min) && !v.After(r.Max), "Value %s out of range", v)
+		tu.Assert(t, v.After(time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)), "Value %s is not recent", v)
+	}
+	tu.Assert(t, len(d.String()) == len("2020-01-01 00:00:00.000"), "Invalid datetime %s", d.String())
+
+	r, err = ParseDateInRangeBounds("date", "2020-01-01", "2020-01-02")
+	tu.Ok(t, err)
+	d = NewDistributedDateInRange("f", "date", 0, r, NewWeights([]float64{0, 1}), false, rnd)
+	tu.Equals(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), d.Value())
+
+	// The values in the last second of the range don't have a fraction
+	r, err = ParseDateInRangeBounds("datetime", "2020-01-01 00:00:00", "2020-01-01 00:00:01")
+	tu.Ok(t, err)
+	d = NewDistributedDateInRange("f", "datetime", 6, r, NewWeights([]float64{0, 1}), false, rnd)
+	for i := 0; i < 10; i++ {
+		tu.Equals(t, r.Max, d.Value())
+	}
+
+	s := NewDistributedSample("f", []interface{}{"a", "b", int64(1)}, NewWeights([]float64{0, 0, 1}), false, rnd)
+	tu.Equals(t, "1", s.Quote())
+	s = NewDistributedSample("f", []interface{}{"a", "b"}, NewWeights([]float64{0, 1}), false, rnd)
+	tu.Equals(t, "'b'", s.Quote())
+}
@@ -1,21 +1,19 @@
 package getters
 
-import (
-	"fmt"
-	"math/rand"
-)
+import "math/rand"
 
 // RandomEnum Getter
 type RandomEnum struct {
 	allowedValues []string
 	allowNull     bool
+	rnd           *rand.Rand
 }
 
 func (r *RandomEnum) Value() interface{} {
-	if r.allowNull && rand.Int63n(100) < nilFrequency {
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
 		return nil
 	}
-	i := rand.Int63n(int64(len(r.allowedValues)))
+	i := r.rnd.Int63n(int64(len(r.allowedValues)))
 	return r.allowedValues[i]
 }
 
@@ -28,11 +26,11 @@ func (r *RandomEnum) String() string {
 
 func (r *RandomEnum) Quote() string {
 	if v := r.Value(); v != nil {
-		return fmt.Sprintf("%q", v)
+		return QuoteString(v.(string))
 	}
 	return "NULL"
 }
 
-func NewRandomEnum(allowedValues []string, allowNull bool) *RandomEnum {
-	return &RandomEnum{allowedValues, allowNull}
+func NewRandomEnum(allowedValues []string, allowNull bool, rnd *rand.Rand) *RandomEnum {
+	return &RandomEnum{allowedValues, allowNull, rnd}
 }
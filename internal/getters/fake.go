@@ -0,0 +1,318 @@
+package getters
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/corpix/uarand"
+	"github.com/icrowley/fake"
+)
+
+// maxFakeTries is the number of values a RandomFake getter generates looking for one that fits in the field
+// before truncating it
+const maxFakeTries = 10
+
+// fakeFuncs maps the kinds of values generated by RandomFake to their generators
+var fakeFuncs = map[string]func(rnd *rand.Rand) string{
+	"city":           func(rnd *rand.Rand) string { return fakeValue(rnd, "cities") },
+	"color":          func(rnd *rand.Rand) string { return fakeValue(rnd, "colors") },
+	"company":        func(rnd *rand.Rand) string { return fakeValue(rnd, "companies") },
+	"country":        func(rnd *rand.Rand) string { return fakeValue(rnd, "countries") },
+	"credit_card":    randomCreditCard,
+	"currency":       func(rnd *rand.Rand) string { return fakeValue(rnd, "currency_codes") },
+	"domain":         randomDomain,
+	"email":          func(rnd *rand.Rand) string { return strings.ToLower(randomUserName(rnd) + "@" + randomDomain(rnd)) },
+	"first_name":     randomFirstName,
+	"full_name":      randomFullName,
+	"gender":         func(rnd *rand.Rand) string { return fakeValue(rnd, "genders") },
+	"iban":           randomIBAN,
+	"ip":             func(rnd *rand.Rand) string { return randomIP(rnd, net.IPv4len) },
+	"ipv6":           func(rnd *rand.Rand) string { return randomIP(rnd, net.IPv6len) },
+	"job_title":      randomJobTitle,
+	"language":       func(rnd *rand.Rand) string { return fakeValue(rnd, "languages") },
+	"last_name":      func(rnd *rand.Rand) string { return fakeValue(rnd, randomGender(rnd)+"_last_names") },
+	"paragraph":      randomParagraph,
+	"phone":          func(rnd *rand.Rand) string { return fakeFormat(rnd, "phones_format") },
+	"product":        randomProduct,
+	"sentence":       randomSentence,
+	"state":          func(rnd *rand.Rand) string { return fakeValue(rnd, "states") },
+	"street_address": randomStreetAddress,
+	"title":          randomTitle,
+	"url":            randomURL,
+	"user_agent":     func(rnd *rand.Rand) string { return uarand.UserAgents[rnd.Intn(len(uarand.UserAgents))] },
+	"user_name":      randomUserName,
+	"word":           func(rnd *rand.Rand) string { return fakeValue(rnd, "words") },
+	"zip":            func(rnd *rand.Rand) string { return fakeFormat(rnd, "zips_format") },
+}
+
+// fakeLists caches the lists of values of the fake package data, like the cities or the first names,
+// loaded on first use
+var (
+	fakeListsMu sync.Mutex
+	fakeLists   = make(map[string][]string)
+)
+
+// fakeList returns the list of values of a category of the fake package data
+func fakeList(category string) []string {
+	fakeListsMu.Lock()
+	defer fakeListsMu.Unlock()
+	if list, ok := fakeLists[category]; ok {
+		return list
+	}
+	var list []string
+	if data, err := fake.FSString(false, "/data/en/"+category); err == nil {
+		list = strings.Split(strings.TrimSpace(data), "\n")
+	}
+	fakeLists[category] = list
+	return list
+}
+
+// fakeValue returns a random value of a category of the fake package data. The values are chosen using
+// rnd instead of the global random source of the fake package, so the values of a field only depend on
+// its own random source
+func fakeValue(rnd *rand.Rand, category string) string {
+	list := fakeList(category)
+	if len(list) == 0 {
+		return ""
+	}
+	return list[rnd.Intn(len(list))]
+}
+
+// fakeFormat returns a value built from a random format of a category, like phone numbers, replacing
+// each # by a random digit
+func fakeFormat(rnd *rand.Rand, category string) string {
+	format := []byte(fakeValue(rnd, category))
+	for i, c := range format {
+		if c == '#' {
+			format[i] = byte('0' + rnd.Intn(10))
+		}
+	}
+	return string(format)
+}
+
+func randomGender(rnd *rand.Rand) string {
+	if rnd.Intn(2) == 0 {
+		return "female"
+	}
+	return "male"
+}
+
+func randomFirstName(rnd *rand.Rand) string {
+	return fakeValue(rnd, randomGender(rnd)+"_first_names")
+}
+
+// randomFullName returns a first and last name and, for 1 in 10 names, a prefix like Mr.
+func randomFullName(rnd *rand.Rand) string {
+	gender := randomGender(rnd)
+	name := fakeValue(rnd, gender+"_first_names") + " " + fakeValue(rnd, gender+"_last_names")
+	if rnd.Intn(10) == 0 {
+		name = fakeValue(rnd, gender+"_name_prefixes") + " " + name
+	}
+	return name
+}
+
+func randomUserName(rnd *rand.Rand) string {
+	gender := randomGender(rnd)
+	switch rnd.Intn(3) {
+	case 0:
+		return fakeValue(rnd, gender+"_first_names") + fakeValue(rnd, gender+"_last_names")
+	case 1:
+		return fakeValue(rnd, "characters") + fakeValue(rnd, gender+"_last_names")
+	}
+	return strings.Replace(randomWords(rnd, rnd.Intn(3)+1), " ", "_", -1)
+}
+
+func randomDomain(rnd *rand.Rand) string {
+	return strings.ToLower(fakeValue(rnd, "companies") + "." + fakeValue(rnd, "top_level_domains"))
+}
+
+func randomIP(rnd *rand.Rand, size int) string {
+	ip := make(net.IP, size)
+	for i := range ip {
+		ip[i] = byte(rnd.Intn(256))
+	}
+	return ip.String()
+}
+
+func randomJobTitle(rnd *rand.Rand) string {
+	return strings.Replace(fakeValue(rnd, "jobs"), "#{N}", fakeValue(rnd, "jobs_suffixes"), 1)
+}
+
+func randomProduct(rnd *rand.Rand) string {
+	product := fakeValue(rnd, "adjectives") + " " + fakeValue(rnd, "nouns")
+	if rnd.Intn(2) == 1 {
+		product = fakeValue(rnd, "adjectives") + " " + product
+	}
+	return product
+}
+
+func randomStreetAddress(rnd *rand.Rand) string {
+	return fmt.Sprintf("%s %s %d", fakeValue(rnd, "streets"), fakeValue(rnd, "street_suffixes"), rnd.Intn(100))
+}
+
+func randomWords(rnd *rand.Rand, n int) string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = fakeValue(rnd, "words")
+	}
+	return strings.Join(words, " ")
+}
+
+// randomTitle returns 2 to 5 words, the first one capitalized
+func randomTitle(rnd *rand.Rand) string {
+	title := randomWords(rnd, 2+rnd.Intn(4))
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
+// randomSentence returns 3 to 14 words, some of them followed by a comma, ending in a period or, for 1 in
+// 8 sentences, in an exclamation mark
+func randomSentence(rnd *rand.Rand) string {
+	words := make([]string, 3+rnd.Intn(12))
+	for i := range words {
+		words[i] = fakeValue(rnd, "words")
+		if i < len(words)-1 && rnd.Intn(5) == 0 {
+			words[i] += ","
+		}
+	}
+	if rnd.Intn(8) == 0 {
+		return strings.Join(words, " ") + "!"
+	}
+	return strings.Join(words, " ") + "."
+}
+
+func randomParagraph(rnd *rand.Rand) string {
+	sentences := make([]string, rnd.Intn(10)+1)
+	for i := range sentences {
+		sentences[i] = randomSentence(rnd)
+	}
+	return strings.Join(sentences, " ")
+}
+
+// FakeKinds returns the kinds of values RandomFake can generate, sorted by name
+func FakeKinds() []string {
+	kinds := make([]string, 0, len(fakeFuncs))
+	for kind := range fakeFuncs {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// RandomFake getter. Generates realistic values like emails, phone numbers or city names
+type RandomFake struct {
+	name      string
+	fn        func(rnd *rand.Rand) string
+	maxSize   int64
+	allowNull bool
+	rnd       *rand.Rand
+}
+
+// Value returns a value of the getter's kind. Values longer than the field are generated again and,
+// if they still don't fit, truncated
+func (r *RandomFake) Value() interface{} {
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
+		return nil
+	}
+	var s string
+	for i := 0; i < maxFakeTries; i++ {
+		if s = r.fn(r.rnd); r.maxSize <= 0 || int64(len(s)) <= r.maxSize {
+			return s
+		}
+	}
+	return s[:r.maxSize]
+}
+
+func (r *RandomFake) String() string {
+	v := r.Value()
+	if v == nil {
+		return NULL
+	}
+	return v.(string)
+}
+
+func (r *RandomFake) Quote() string {
+	v := r.Value()
+	if v == nil {
+		return NULL
+	}
+	return QuoteString(v.(string))
+}
+
+// NewRandomFake returns a getter for values of the kind, one of FakeKinds, up to maxSize bytes long.
+// A maxSize of 0 means no limit.
+func NewRandomFake(name, kind string, maxSize int64, allowNull bool, rnd *rand.Rand) (*RandomFake, error) {
+	fn, ok := fakeFuncs[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown kind %q. Valid kinds are: %s", kind, strings.Join(FakeKinds(), ", "))
+	}
+	return &RandomFake{name, fn, maxSize, allowNull, rnd}, nil
+}
+
+func randomURL(rnd *rand.Rand) string {
+	return "https://www." + randomDomain(rnd) + "/" + strings.ToLower(fakeValue(rnd, "words"))
+}
+
+// creditCards are the prefixes and lengths of the credit card numbers generated
+var creditCards = []struct {
+	prefixes []string
+	length   int
+}{
+	{[]string{"4539", "4556", "4916", "4532", "4929", "4485", "4716"}, 16}, // Visa
+	{[]string{"51", "52", "53", "54", "55"}, 16},                           // MasterCard
+	{[]string{"34", "37"}, 15},                                             // American Express
+	{[]string{"6011"}, 16},                                                 // Discover
+}
+
+// randomCreditCard returns a credit card number having a valid Luhn check digit
+func randomCreditCard(rnd *rand.Rand) string {
+	card := creditCards[rnd.Intn(len(creditCards))]
+	num := []byte(card.prefixes[rnd.Intn(len(card.prefixes))])
+	for len(num) < card.length-1 {
+		num = append(num, byte('0'+rnd.Intn(10)))
+	}
+	// Starting from the check digit, every second digit is doubled
+	sum := 0
+	for i := len(num) - 1; i >= 0; i -= 2 {
+		d := int(num[i]-'0') * 2
+		if d > 9 {
+			d -= 9
+		}
+		sum += d
+		if i > 0 {
+			sum += int(num[i-1] - '0')
+		}
+	}
+	return string(append(num, byte('0'+(10-sum%10)%10)))
+}
+
+// ibanFormats are the country codes and the number of digits of the account numbers of the IBANs
+// generated. Only countries having numeric account numbers are used.
+var ibanFormats = []struct {
+	country string
+	digits  int
+}{{"BE", 12}, {"DE", 18}, {"ES", 20}, {"PL", 24}, {"PT", 21}}
+
+// randomIBAN returns an IBAN having valid check digits
+func randomIBAN(rnd *rand.Rand) string {
+	format := ibanFormats[rnd.Intn(len(ibanFormats))]
+	account := make([]byte, format.digits)
+	for i := range account {
+		account[i] = byte('0' + rnd.Intn(10))
+	}
+
+	// The check digits are 98 - (account + country + "00") mod 97, with the letters
+	// of the country code replaced by numbers (A = 10, B = 11, ...)
+	num := string(account)
+	for _, c := range format.country {
+		num += fmt.Sprintf("%d", c-'A'+10)
+	}
+	n, _ := new(big.Int).SetString(num+"00", 10)
+	check := 98 - new(big.Int).Mod(n, big.NewInt(97)).Int64()
+
+	return fmt.Sprintf("%s%02d%s", format.country, check, account)
+}
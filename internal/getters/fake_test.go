@@ -0,0 +1,101 @@
+package getters
+
+import (
+	"math/big"
+	"math/rand"
+	"regexp"
+	"strings"
+	"testing"
+
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestRandomFake(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	patterns := map[string]string{
+		"email": `^\S+@\S+\.\S+$`,
+		"ip":    `^\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}$`,
+		"url":   `^https://www\.\S+/\S+$`,
+		"iban":  `^[A-Z]{2}\d{14,26}$`,
+	}
+	for kind, pattern := range patterns {
+		re := regexp.MustCompile(pattern)
+		g, err := NewRandomFake("f1", kind, 0, false, rnd)
+		tu.Ok(t, err)
+		for i := 0; i < 100; i++ {
+			v := g.String()
+			tu.Assert(t, re.MatchString(v), "Invalid %s %q", kind, v)
+		}
+	}
+
+	for _, kind := range FakeKinds() {
+		g, err := NewRandomFake("f1", kind, 0, false, rnd)
+		tu.Ok(t, err)
+		tu.Assert(t, strings.TrimSpace(g.String()) != "", "Empty %s value", kind)
+	}
+
+	for _, kind := range FakeKinds() {
+		g, err := NewRandomFake("f1", kind, 8, false, rnd)
+		tu.Ok(t, err)
+		for i := 0; i < 100; i++ {
+			v := g.String()
+			tu.Assert(t, len(v) <= 8, "%s value %q is longer than 8 bytes", kind, v)
+		}
+	}
+
+	_, err := NewRandomFake("f1", "nope", 10, false, rnd)
+	tu.NotOk(t, err)
+
+	// Getters using the same seed generate the same values, whatever the other getters generate
+	values := func(other bool) []string {
+		g, err := NewRandomFake("f1", "full_name", 0, false, rand.New(rand.NewSource(2)))
+		tu.Ok(t, err)
+		s := NewRandomString("f2", 50, false, rand.New(rand.NewSource(3)))
+		o, err := NewRandomFake("f3", "city", 0, false, rand.New(rand.NewSource(4)))
+		tu.Ok(t, err)
+		var v []string
+		for i := 0; i < 10; i++ {
+			v = append(v, g.String(), s.String())
+			if other {
+				o.Value()
+			}
+		}
+		return v
+	}
+	tu.Equals(t, values(false), values(true))
+}
+
+func TestRandomCreditCard(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		num := randomCreditCard(rnd)
+		tu.Assert(t, len(num) == 15 || len(num) == 16, "Invalid credit card number %s", num)
+		sum := 0
+		for j := range num {
+			d := int(num[len(num)-1-j] - '0')
+			if j%2 == 1 {
+				if d *= 2; d > 9 {
+					d -= 9
+				}
+			}
+			sum += d
+		}
+		tu.Equals(t, 0, sum%10)
+	}
+}
+
+func TestRandomIBAN(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		iban := randomIBAN(rnd)
+		// Valid IBANs, moving the first 4 chars to the end, are 1 mod 97
+		num := iban[4:]
+		for _, c := range iban[:2] {
+			num += string(rune('0'+(c-'A'+10)/10)) + string(rune('0'+(c-'A'+10)%10))
+		}
+		n, ok := new(big.Int).SetString(num+iban[2:4], 10)
+		tu.Assert(t, ok && !strings.ContainsAny(iban[2:], "ABCDEFGHIJKLMNOPQRSTUVWXYZ"), "Invalid IBAN %s", iban)
+		tu.Equals(t, int64(1), new(big.Int).Mod(n, big.NewInt(97)).Int64())
+	}
+}
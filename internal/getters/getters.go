@@ -1,14 +1,58 @@
 package getters
 
-// All types defined here satisfy the Getter interface
-// type Getter interface {
-// 	   Value()  interface{}
-//     Quote()  string
-// 	   String() string
-// }
+import (
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+// Getter is the interface satisfied by all the types defined in this package
+type Getter interface {
+	Value() interface{}
+	Quote() string
+	String() string
+}
+
+// Formatter is implemented by the getters formatting their values differently than FormatValue's defaults,
+// like dates or decimals. It lets the getters wrapping other getters format the values they took from them.
+type Formatter interface {
+	// Format returns a value returned by Value formatted like String or, if quote is true, like Quote
+	Format(v interface{}, quote bool) string
+}
+
+// FormatValue formats a value returned by the Value method of g like its String or, if quote is true, its
+// Quote method
+func FormatValue(g Getter, v interface{}, quote bool) string {
+	if f, ok := g.(Formatter); ok && v != nil {
+		return f.Format(v, quote)
+	}
+	if quote {
+		return QuoteValue(v)
+	}
+	return valueString(v)
+}
 
 const (
 	nilFrequency = 10
-	oneYear      = int64(60 * 60 * 24 * 365)
 	NULL         = "NULL"
 )
+
+var referenceTime = time.Now()
+
+// SetReferenceTime sets the time used as "now" by the getters generating dates relative
+// to the current date, so runs using the same seed and reference time generate the same values.
+func SetReferenceTime(t time.Time) {
+	referenceTime = t
+}
+
+func now() time.Time {
+	return referenceTime
+}
+
+// NewColumnRand returns a random source for a column. Each column has its own source, derived from the
+// run seed and the column name, so the values generated for a column don't depend on the other columns.
+func NewColumnRand(seed int64, schema, table, column string) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(schema + "." + table + "." + column)) // nolint: errcheck
+	return rand.New(rand.NewSource(seed ^ int64(h.Sum64())))
+}
@@ -2,51 +2,162 @@ package getters
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 )
 
+// signedRanges holds the range of values for signed integer types
+var signedRanges = map[string][2]int64{
+	"tinyint":   {math.MinInt8, math.MaxInt8},
+	"smallint":  {math.MinInt16, math.MaxInt16},
+	"mediumint": {-1 << 23, 1<<23 - 1},
+	"int":       {math.MinInt32, math.MaxInt32},
+	"integer":   {math.MinInt32, math.MaxInt32},
+	"bigint":    {math.MinInt64, math.MaxInt64},
+}
+
+// unsignedMaxValues holds the maximum value for unsigned integer types
+var unsignedMaxValues = map[string]uint64{
+	"tinyint":   math.MaxUint8,
+	"smallint":  math.MaxUint16,
+	"mediumint": 1<<24 - 1,
+	"int":       math.MaxUint32,
+	"integer":   math.MaxUint32,
+	"bigint":    math.MaxUint64,
+}
+
+// IntRange returns the range of values for a signed integer type.
+// Unknown types get the bigint range.
+func IntRange(dataType string) (int64, int64) {
+	if r, ok := signedRanges[dataType]; ok {
+		return r[0], r[1]
+	}
+	return math.MinInt64, math.MaxInt64
+}
+
+// IsIntegerType returns true if the data type is one of the integer types
+func IsIntegerType(dataType string) bool {
+	_, ok := signedRanges[dataType]
+	return ok
+}
+
+// UintMaxValue returns the maximum value for an unsigned integer type.
+// Unknown types get the bigint maximum value.
+func UintMaxValue(dataType string) uint64 {
+	if m, ok := unsignedMaxValues[dataType]; ok {
+		return m
+	}
+	return math.MaxUint64
+}
+
+// randomUint64 returns a random number in the [0, n] range
+func randomUint64(rnd *rand.Rand, n uint64) uint64 {
+	if n == math.MaxUint64 {
+		return rnd.Uint64()
+	}
+	n++
+	// Discard the values in the last incomplete [0, n) interval to avoid modulo bias
+	rem := (math.MaxUint64%n + 1) % n
+	for {
+		v := rnd.Uint64()
+		if rem == 0 || v <= math.MaxUint64-rem {
+			return v % n
+		}
+	}
+}
+
 type RandomInt struct {
 	name      string
 	mask      int64
 	allowNull bool
+	rnd       *rand.Rand
 }
 
 func (r *RandomInt) Value() interface{} {
-	return rand.Int63n(r.mask)
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
+		return nil
+	}
+	return r.rnd.Int63n(r.mask)
 }
 
 func (r *RandomInt) String() string {
-	return fmt.Sprintf("%d", r.Value())
+	v := r.Value()
+	if v == nil {
+		return NULL
+	}
+	return fmt.Sprintf("%d", v)
 }
 
 func (r *RandomInt) Quote() string {
 	return r.String()
 }
 
-func NewRandomInt(name string, mask int64, allowNull bool) *RandomInt {
-	return &RandomInt{name, mask, allowNull}
+func NewRandomInt(name string, mask int64, allowNull bool, rnd *rand.Rand) *RandomInt {
+	return &RandomInt{name, mask, allowNull, rnd}
 }
 
+// RandomIntRange returns random int64 values in the [min, max] range
 type RandomIntRange struct {
 	name      string
 	min       int64
 	max       int64
 	allowNull bool
+	rnd       *rand.Rand
 }
 
 func (r *RandomIntRange) Value() interface{} {
-	limit := r.max - r.min + 1
-	return r.min + rand.Int63n(limit)
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
+		return nil
+	}
+	// max - min doesn't fit into an int64 for big ranges but it always fits into an uint64
+	return r.min + int64(randomUint64(r.rnd, uint64(r.max-r.min)))
 }
 
 func (r *RandomIntRange) String() string {
-	return fmt.Sprintf("%d", r.Value())
+	v := r.Value()
+	if v == nil {
+		return NULL
+	}
+	return fmt.Sprintf("%d", v)
 }
 
 func (r *RandomIntRange) Quote() string {
 	return r.String()
 }
 
-func NewRandomIntRange(name string, min, max int64, allowNull bool) *RandomIntRange {
-	return &RandomIntRange{name, min, max, allowNull}
+func NewRandomIntRange(name string, min, max int64, allowNull bool, rnd *rand.Rand) *RandomIntRange {
+	return &RandomIntRange{name, min, max, allowNull, rnd}
+}
+
+// RandomUintRange returns random uint64 values in the [min, max] range.
+// Used for unsigned columns since unsigned bigint values don't fit into an int64
+type RandomUintRange struct {
+	name      string
+	min       uint64
+	max       uint64
+	allowNull bool
+	rnd       *rand.Rand
+}
+
+func (r *RandomUintRange) Value() interface{} {
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
+		return nil
+	}
+	return r.min + randomUint64(r.rnd, r.max-r.min)
+}
+
+func (r *RandomUintRange) String() string {
+	v := r.Value()
+	if v == nil {
+		return NULL
+	}
+	return fmt.Sprintf("%d", v)
+}
+
+func (r *RandomUintRange) Quote() string {
+	return r.String()
+}
+
+func NewRandomUintRange(name string, min, max uint64, allowNull bool, rnd *rand.Rand) *RandomUintRange {
+	return &RandomUintRange{name, min, max, allowNull, rnd}
 }
@@ -0,0 +1,54 @@
+package getters
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestRandomIntRange(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, dataType := range []string{"tinyint", "smallint", "mediumint", "int", "bigint"} {
+		min, max := IntRange(dataType)
+		r := NewRandomIntRange("f1", min, max, false, rnd)
+		negatives := 0
+		for i := 0; i < 1000; i++ {
+			v := r.Value().(int64)
+			tu.Assert(t, v >= min && v <= max, "%s value %d out of range [%d, %d]", dataType, v, min, max)
+			if v < 0 {
+				negatives++
+			}
+		}
+		tu.Assert(t, negatives > 0, "No negative values for %s", dataType)
+	}
+
+	r := NewRandomIntRange("f1", -1, 1, true, rnd)
+	seen := map[interface{}]bool{}
+	for i := 0; i < 1000; i++ {
+		seen[r.Value()] = true
+	}
+	tu.Equals(t, map[interface{}]bool{int64(-1): true, int64(0): true, int64(1): true, nil: true}, seen)
+}
+
+func TestRandomUintRange(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	tu.Equals(t, uint64(math.MaxUint8), UintMaxValue("tinyint"))
+	tu.Equals(t, uint64(math.MaxUint64), UintMaxValue("bigint"))
+
+	r := NewRandomUintRange("f1", 0, math.MaxUint64, false, rnd)
+	big := 0
+	for i := 0; i < 1000; i++ {
+		if r.Value().(uint64) > math.MaxInt64 {
+			big++
+		}
+	}
+	tu.Assert(t, big > 0, "No values > MaxInt64 for unsigned bigint")
+
+	r = NewRandomUintRange("f1", 250, 255, false, rnd)
+	for i := 0; i < 1000; i++ {
+		v := r.Value().(uint64)
+		tu.Assert(t, v >= 250 && v <= 255, "value %d out of range [250, 255]", v)
+	}
+}
@@ -0,0 +1,322 @@
+package getters
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	defaultJSONDepth       = 2
+	defaultJSONKeys        = 5
+	defaultJSONArraySize   = 5
+	defaultJSONStringSize  = 10
+	defaultJSONMaxInt      = 1000
+	defaultJSONOptionalPct = 50
+)
+
+// JSONSchema holds the subset of JSON Schema supported by the JSON getter
+type JSONSchema struct {
+	Type       interface{}            `json:"type,omitempty"` // a type name or a list of type names
+	Properties map[string]*JSONSchema `json:"properties,omitempty"`
+	Required   []string               `json:"required,omitempty"`
+	Items      *JSONSchema            `json:"items,omitempty"`
+	MinItems   *int                   `json:"minItems,omitempty"`
+	MaxItems   *int                   `json:"maxItems,omitempty"`
+	MinLength  *int                   `json:"minLength,omitempty"`
+	MaxLength  *int                   `json:"maxLength,omitempty"`
+	Minimum    *float64               `json:"minimum,omitempty"`
+	Maximum    *float64               `json:"maximum,omitempty"`
+	Enum       []interface{}          `json:"enum,omitempty"`
+	Const      interface{}            `json:"const,omitempty"`
+}
+
+// NewJSONSchema parses a JSON Schema document
+func NewJSONSchema(data []byte) (*JSONSchema, error) {
+	var s *JSONSchema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %s", err)
+	}
+	if s == nil {
+		return nil, fmt.Errorf("invalid JSON schema: empty schema")
+	}
+	return s, nil
+}
+
+// NewJSONSchemaFromSample returns a schema describing the structure of a sample document.
+// Documents generated using this schema have the same keys and value types as the sample.
+func NewJSONSchemaFromSample(data []byte) (*JSONSchema, error) {
+	var sample interface{}
+	if err := json.Unmarshal(data, &sample); err != nil {
+		return nil, fmt.Errorf("invalid JSON sample: %s", err)
+	}
+	return schemaFromSample(sample), nil
+}
+
+func schemaFromSample(sample interface{}) *JSONSchema {
+	switch v := sample.(type) {
+	case map[string]interface{}:
+		s := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema)}
+		for key, value := range v {
+			s.Properties[key] = schemaFromSample(value)
+			s.Required = append(s.Required, key)
+		}
+		sort.Strings(s.Required)
+		return s
+	case []interface{}:
+		s := &JSONSchema{Type: "array", MaxItems: intPtr(len(v))}
+		if len(v) > 0 {
+			s.Items = schemaFromSample(v[0])
+			s.MinItems = intPtr(1)
+		}
+		return s
+	case string:
+		return &JSONSchema{Type: "string", MinLength: intPtr(1), MaxLength: intPtr(len(v))}
+	case float64:
+		max := math.Abs(v) * 2
+		if max == 0 {
+			max = defaultJSONMaxInt
+		}
+		min := 0.0
+		if v < 0 {
+			min = -max
+		}
+		if v == math.Trunc(v) {
+			return &JSONSchema{Type: "integer", Minimum: &min, Maximum: &max}
+		}
+		return &JSONSchema{Type: "number", Minimum: &min, Maximum: &max}
+	case bool:
+		return &JSONSchema{Type: "boolean"}
+	default:
+		return &JSONSchema{Type: "null"}
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+// RandomJSON getter. Generates JSON documents following a JSON schema or, if there is no schema,
+// random objects having up to maxDepth nested levels
+type RandomJSON struct {
+	name         string
+	schema       *JSONSchema
+	maxDepth     int
+	keys         []string
+	maxArraySize int
+	allowNull    bool
+	rnd          *rand.Rand
+}
+
+// Value returns a JSON document as a string
+func (r *RandomJSON) Value() interface{} {
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
+		return nil
+	}
+	var doc interface{}
+	if r.schema != nil {
+		doc = r.fromSchema(r.schema, 0)
+	} else {
+		doc = r.randomObject(0)
+	}
+	// Maps keys are sorted by json.Marshal so the output is always the same for the same document
+	b, _ := json.Marshal(doc)
+	return string(b)
+}
+
+func (r *RandomJSON) String() string {
+	v := r.Value()
+	if v == nil {
+		return NULL
+	}
+	return v.(string)
+}
+
+func (r *RandomJSON) Quote() string {
+	v := r.Value()
+	if v == nil {
+		return NULL
+	}
+	return QuoteString(v.(string))
+}
+
+func (r *RandomJSON) randomObject(depth int) map[string]interface{} {
+	obj := make(map[string]interface{})
+	count := 1 + r.rnd.Intn(defaultJSONKeys)
+	if len(r.keys) > 0 {
+		count = 1 + r.rnd.Intn(len(r.keys))
+	}
+	for i := 0; i < count; i++ {
+		key := r.randomString(3, defaultJSONStringSize)
+		if len(r.keys) > 0 {
+			key = r.keys[r.rnd.Intn(len(r.keys))]
+		}
+		obj[key] = r.randomValue(depth + 1)
+	}
+	return obj
+}
+
+func (r *RandomJSON) randomValue(depth int) interface{} {
+	kinds := 5
+	if depth < r.maxDepth {
+		kinds = 7
+	}
+	switch r.rnd.Intn(kinds) {
+	case 0:
+		return r.randomString(1, defaultJSONStringSize)
+	case 1:
+		return r.rnd.Int63n(defaultJSONMaxInt)
+	case 2:
+		return r.rnd.Float64() * defaultJSONMaxInt
+	case 3:
+		return r.rnd.Intn(2) == 0
+	case 4:
+		return nil
+	case 5:
+		return r.randomObject(depth)
+	default:
+		arr := make([]interface{}, r.rnd.Intn(r.maxArraySize+1))
+		for i := range arr {
+			arr[i] = r.randomValue(depth + 1)
+		}
+		return arr
+	}
+}
+
+func (r *RandomJSON) randomString(minSize, maxSize int) string {
+	b := make([]byte, minSize+r.rnd.Intn(maxSize-minSize+1))
+	for i := range b {
+		b[i] = byte('a' + r.rnd.Intn(26))
+	}
+	return string(b)
+}
+
+func (r *RandomJSON) fromSchema(s *JSONSchema, depth int) interface{} {
+	if s.Const != nil {
+		return s.Const
+	}
+	if len(s.Enum) > 0 {
+		return s.Enum[r.rnd.Intn(len(s.Enum))]
+	}
+
+	switch r.schemaType(s) {
+	case "object":
+		obj := make(map[string]interface{})
+		required := make(map[string]bool)
+		for _, key := range s.Required {
+			required[key] = true
+		}
+		// Properties must be processed always in the same order to get the same values for the same seed
+		keys := make([]string, 0, len(s.Properties))
+		for key := range s.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if required[key] || r.rnd.Intn(100) < defaultJSONOptionalPct {
+				obj[key] = r.fromSchema(s.Properties[key], depth+1)
+			}
+		}
+		return obj
+	case "array":
+		min, max := intRange(s.MinItems, s.MaxItems, 0, r.maxArraySize)
+		arr := make([]interface{}, min+r.rnd.Intn(max-min+1))
+		for i := range arr {
+			if s.Items == nil {
+				arr[i] = r.randomValue(depth + 1)
+				continue
+			}
+			arr[i] = r.fromSchema(s.Items, depth+1)
+		}
+		return arr
+	case "string":
+		min, max := intRange(s.MinLength, s.MaxLength, 1, defaultJSONStringSize)
+		return r.randomString(min, max)
+	case "integer":
+		min, max := floatRange(s.Minimum, s.Maximum)
+		min, max = math.Ceil(min), math.Floor(max)
+		if max < min {
+			max = min
+		}
+		return int64(min) + int64(randomUint64(r.rnd, uint64(max-min)))
+	case "number":
+		min, max := floatRange(s.Minimum, s.Maximum)
+		return min + r.rnd.Float64()*(max-min)
+	case "boolean":
+		return r.rnd.Intn(2) == 0
+	case "null":
+		return nil
+	default:
+		return r.randomValue(depth)
+	}
+}
+
+// schemaType returns the type for the value to be generated. If the schema has a list of types,
+// one of them is randomly selected. If the schema has no type, it is inferred from the other keywords
+func (r *RandomJSON) schemaType(s *JSONSchema) string {
+	switch t := s.Type.(type) {
+	case string:
+		return t
+	case []interface{}:
+		if len(t) > 0 {
+			if name, ok := t[r.rnd.Intn(len(t))].(string); ok {
+				return name
+			}
+		}
+	}
+	if s.Properties != nil {
+		return "object"
+	}
+	if s.Items != nil {
+		return "array"
+	}
+	return ""
+}
+
+func intRange(min, max *int, defMin, defMax int) (int, int) {
+	lo, hi := defMin, defMax
+	if min != nil {
+		lo = *min
+	}
+	if max != nil {
+		hi = *max
+	} else if hi < lo {
+		hi = lo + defMax
+	}
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
+
+func floatRange(min, max *float64) (float64, float64) {
+	lo, hi := 0.0, float64(defaultJSONMaxInt)
+	if min != nil {
+		lo = *min
+	}
+	if max != nil {
+		hi = *max
+	} else if hi < lo {
+		hi = lo + defaultJSONMaxInt
+	}
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
+
+// NewRandomJSON returns a getter for JSON fields. If schema is nil, it generates random objects
+// having up to maxDepth nested levels, using the keys in the list (or random strings if the list
+// is empty) and arrays having up to maxArraySize elements.
+func NewRandomJSON(name string, schema *JSONSchema, maxDepth int, keys []string, maxArraySize int,
+	allowNull bool, rnd *rand.Rand) *RandomJSON {
+	if maxDepth < 1 {
+		maxDepth = defaultJSONDepth
+	}
+	if maxArraySize < 1 {
+		maxArraySize = defaultJSONArraySize
+	}
+	return &RandomJSON{name, schema, maxDepth, keys, maxArraySize, allowNull, rnd}
+}
@@ -0,0 +1,76 @@
+package getters
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestRandomJSON(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	r := NewRandomJSON("f1", nil, 2, []string{"a", "b"}, 3, false, rnd)
+	for i := 0; i < 100; i++ {
+		var doc map[string]interface{}
+		tu.Ok(t, json.Unmarshal([]byte(r.String()), &doc))
+		for key := range doc {
+			tu.Assert(t, key == "a" || key == "b", "Invalid key %q", key)
+		}
+	}
+}
+
+func TestRandomJSONSchema(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	schema, err := NewJSONSchema([]byte(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 3, "maxLength": 5},
+			"age": {"type": "integer", "minimum": 18, "maximum": 99},
+			"status": {"enum": ["active", "inactive"]},
+			"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2}
+		},
+		"required": ["name", "age", "status"]
+	}`))
+	tu.Ok(t, err)
+
+	r := NewRandomJSON("f1", schema, 0, nil, 0, false, rnd)
+	for i := 0; i < 100; i++ {
+		var doc struct {
+			Name   *string
+			Age    *int
+			Status string
+			Tags   []string
+		}
+		tu.Ok(t, json.Unmarshal([]byte(r.String()), &doc))
+		tu.Assert(t, doc.Name != nil && len(*doc.Name) >= 3 && len(*doc.Name) <= 5, "Invalid name %v", doc.Name)
+		tu.Assert(t, doc.Age != nil && *doc.Age >= 18 && *doc.Age <= 99, "Invalid age %v", doc.Age)
+		tu.Assert(t, doc.Status == "active" || doc.Status == "inactive", "Invalid status %q", doc.Status)
+		tu.Assert(t, len(doc.Tags) <= 2, "Invalid tags %v", doc.Tags)
+	}
+
+	_, err = NewJSONSchema([]byte(`{"type": `))
+	tu.NotOk(t, err)
+}
+
+func TestRandomJSONSample(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	schema, err := NewJSONSchemaFromSample([]byte(`{"id": 10, "name": "john", "address": {"zip": "12345"}}`))
+	tu.Ok(t, err)
+
+	r := NewRandomJSON("f1", schema, 0, nil, 0, false, rnd)
+	for i := 0; i < 100; i++ {
+		var doc map[string]interface{}
+		tu.Ok(t, json.Unmarshal([]byte(r.String()), &doc))
+		tu.Equals(t, 3, len(doc))
+		_, ok := doc["id"].(float64)
+		tu.Assert(t, ok, "Invalid id %v", doc["id"])
+		_, ok = doc["name"].(string)
+		tu.Assert(t, ok, "Invalid name %v", doc["name"])
+		address, ok := doc["address"].(map[string]interface{})
+		tu.Assert(t, ok, "Invalid address %v", doc["address"])
+		_, ok = address["zip"].(string)
+		tu.Assert(t, ok, "Invalid zip %v", address["zip"])
+	}
+}
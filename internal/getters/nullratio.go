@@ -0,0 +1,45 @@
+package getters
+
+import "math/rand"
+
+// NullRatio wraps a getter to return NULLs with a user defined frequency
+type NullRatio struct {
+	getter Getter
+	ratio  float64
+	rnd    *rand.Rand
+}
+
+func (r *NullRatio) isNull() bool {
+	return r.rnd.Float64() < r.ratio
+}
+
+func (r *NullRatio) Value() interface{} {
+	if r.isNull() {
+		return nil
+	}
+	return r.getter.Value()
+}
+
+func (r *NullRatio) String() string {
+	if r.isNull() {
+		return NULL
+	}
+	return r.getter.String()
+}
+
+func (r *NullRatio) Quote() string {
+	if r.isNull() {
+		return NULL
+	}
+	return r.getter.Quote()
+}
+
+func (r *NullRatio) Format(v interface{}, quote bool) string {
+	return FormatValue(r.getter, v, quote)
+}
+
+// NewNullRatio returns a getter that returns NULL for the given ratio (0 ~ 1) of the values
+// and the values from the underlying getter for the rest
+func NewNullRatio(getter Getter, ratio float64, rnd *rand.Rand) *NullRatio {
+	return &NullRatio{getter, ratio, rnd}
+}
@@ -0,0 +1,269 @@
+package getters
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Sequence getter. Generates consecutive integers, like an auto increment field
+type Sequence struct {
+	name     string
+	next     uint64
+	unsigned bool
+}
+
+// Value returns the next number in the sequence as an int64, or as an uint64 for unsigned sequences
+func (r *Sequence) Value() interface{} {
+	v := r.next
+	r.next++
+	if r.unsigned {
+		return v
+	}
+	return int64(v)
+}
+
+func (r *Sequence) String() string {
+	return valueString(r.Value())
+}
+
+func (r *Sequence) Quote() string {
+	return r.String()
+}
+
+// NewSequence returns a new sequence starting at start
+func NewSequence(name string, start int64) *Sequence {
+	return &Sequence{name, uint64(start), false}
+}
+
+// NewUintSequence returns a new sequence of unsigned integers starting at start
+func NewUintSequence(name string, start uint64) *Sequence {
+	return &Sequence{name, start, true}
+}
+
+// uuidEpoch is the number of 100 ns intervals between the UUID epoch (1582-10-15) and the Unix epoch
+const uuidEpoch = 0x01B21DD213814000
+
+// RandomUUID getter. Generates version 1, 4 or 7 UUIDs as strings or as binary(16) values.
+// Version 1 and 7 UUIDs are based on the reference time and are always increasing so they
+// are distinct even if many UUIDs are generated in the same millisecond.
+type RandomUUID struct {
+	name     string
+	version  int
+	binary   bool
+	count    uint64
+	clockSeq uint16
+	node     uint64
+	rnd      *rand.Rand
+}
+
+// Value returns a new UUID as a string or, for binary UUIDs, as a []byte
+func (r *RandomUUID) Value() interface{} {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], r.rnd.Uint64())
+	binary.BigEndian.PutUint64(b[8:], r.rnd.Uint64())
+
+	switch r.version {
+	case 1:
+		t := uint64(now().UnixNano()/100) + uuidEpoch + r.count
+		binary.BigEndian.PutUint32(b[0:], uint32(t))
+		binary.BigEndian.PutUint16(b[4:], uint16(t>>32))
+		binary.BigEndian.PutUint16(b[6:], uint16(t>>48)&0x0fff)
+		binary.BigEndian.PutUint64(b[8:], uint64(r.clockSeq)<<48|r.node)
+	case 7:
+		// 48 bits of milliseconds and a 12 bits counter. The timestamp moves to the next
+		// millisecond when the counter overflows
+		ms := uint64(now().UnixNano()/1e6) + r.count>>12
+		binary.BigEndian.PutUint64(b[0:], ms<<16|r.count&0x0fff)
+	}
+	r.count++
+	b[6] = b[6]&0x0f | byte(r.version<<4)
+	b[8] = b[8]&0x3f | 0x80
+
+	if r.binary {
+		return b[:]
+	}
+	s := hex.EncodeToString(b[:])
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+func (r *RandomUUID) String() string {
+	return valueString(r.Value())
+}
+
+func (r *RandomUUID) Quote() string {
+	return QuoteValue(r.Value())
+}
+
+// NewRandomUUID returns a new UUID getter. version must be 1, 4 or 7; any other version generates
+// version 4 (random) UUIDs. If binary is true, UUIDs are generated as 16 bytes values for binary(16) fields.
+func NewRandomUUID(name string, version int, binary bool, rnd *rand.Rand) *RandomUUID {
+	if version != 1 && version != 7 {
+		version = 4
+	}
+	r := &RandomUUID{name: name, version: version, binary: binary, rnd: rnd}
+	r.clockSeq = uint16(rnd.Int63n(1 << 14))
+	// Random node ids have the multicast bit set
+	r.node = uint64(rnd.Int63n(1<<48)) | 1<<40
+	if version == 7 {
+		r.count = uint64(rnd.Int63n(1 << 11))
+	}
+	return r
+}
+
+// crockfordBase32 is the alphabet used by ULIDs. base32Digits are the digits used by big.Int.Text(32)
+const (
+	crockfordBase32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	base32Digits    = "0123456789abcdefghijklmnopqrstuv"
+)
+
+// RandomULID getter. Generates ULIDs: 48 bits of milliseconds since the reference time and 80
+// random bits, encoded as 26 chars. Like in the ULID monotonic mode, the random part is incremented
+// for each new value, so ULIDs are always increasing and distinct.
+type RandomULID struct {
+	name     string
+	randomHi uint16
+	randomLo uint64
+}
+
+// Value returns a new ULID as a string
+func (r *RandomULID) Value() interface{} {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[0:], uint64(now().UnixNano()/1e6)<<16|uint64(r.randomHi))
+	binary.BigEndian.PutUint64(b[8:], r.randomLo)
+	r.randomLo++
+	if r.randomLo == 0 {
+		r.randomHi++
+	}
+
+	digits := new(big.Int).SetBytes(b[:]).Text(32)
+	var s strings.Builder
+	s.WriteString(strings.Repeat("0", 26-len(digits)))
+	for i := 0; i < len(digits); i++ {
+		s.WriteByte(crockfordBase32[strings.IndexByte(base32Digits, digits[i])])
+	}
+	return s.String()
+}
+
+func (r *RandomULID) String() string {
+	return r.Value().(string)
+}
+
+func (r *RandomULID) Quote() string {
+	return QuoteString(r.Value().(string))
+}
+
+// NewRandomULID returns a new ULID getter
+func NewRandomULID(name string, rnd *rand.Rand) *RandomULID {
+	// The highest bit is left unset so the random part doesn't overflow
+	return &RandomULID{name, uint16(rnd.Int63n(1 << 15)), rnd.Uint64()}
+}
+
+// CompositeKey generates the values for primary keys like (tenant_id, id), having a counter per
+// prefix: the prefix fields get values from their own getters and the last field gets the next
+// number for that prefix.
+type CompositeKey struct {
+	prefix   []Getter
+	counters map[string]int64
+	row      uint64
+	current  []interface{}
+}
+
+// NewCompositeKey returns a new composite key. counters has the last value used for each prefix,
+// using PrefixKey to get the prefix key. Prefixes not in counters start at 1.
+func NewCompositeKey(prefix []Getter, counters map[string]int64) *CompositeKey {
+	if counters == nil {
+		counters = make(map[string]int64)
+	}
+	return &CompositeKey{prefix: prefix, counters: counters, current: make([]interface{}, len(prefix)+1)}
+}
+
+// PrefixValue converts a prefix value read from a field of the data type, like the []byte values returned
+// by the MySQL driver, to the type of the values generated for the field, so PrefixKey returns the same key
+// for the prefixes already in the table and for the generated ones
+func PrefixValue(dataType string, v interface{}) interface{} {
+	b, ok := v.([]byte)
+	if !ok {
+		return v
+	}
+	s := string(b)
+	switch dataType {
+	case "date", "datetime", "timestamp":
+		if t, err := ParseDateTime(s); err == nil {
+			return t
+		}
+	case "time":
+		if d, err := parseTime(s); err == nil {
+			return d
+		}
+	case "year":
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n
+		}
+	case "float", "double":
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case "bit":
+		var n uint64
+		for _, c := range b {
+			n = n<<8 | uint64(c)
+		}
+		return n
+	}
+	if IsIntegerType(dataType) {
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n
+		}
+		if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return n
+		}
+	}
+	return s
+}
+
+// PrefixKey returns the key for the prefix values in the counters map
+func PrefixKey(values []interface{}) string {
+	keys := make([]string, 0, len(values))
+	for _, v := range values {
+		keys = append(keys, valueString(v))
+	}
+	return strings.Join(keys, "\x00")
+}
+
+// Parts returns a getter for each prefix field and for the counter field, in that order.
+// All the parts must be used once per row.
+func (k *CompositeKey) Parts() []Getter {
+	return keyParts(k, len(k.prefix)+1)
+}
+
+func (k *CompositeKey) rows() uint64 {
+	return k.row
+}
+
+func (k *CompositeKey) next() {
+	for i, g := range k.prefix {
+		k.current[i] = g.Value()
+	}
+	key := PrefixKey(k.current[:len(k.prefix)])
+	k.counters[key]++
+	k.current[len(k.prefix)] = k.counters[key]
+	k.row++
+}
+
+func (k *CompositeKey) value(pos int) interface{} {
+	return k.current[pos]
+}
+
+func (k *CompositeKey) format(pos int, v interface{}, quote bool) string {
+	if pos < len(k.prefix) {
+		return FormatValue(k.prefix[pos], v, quote)
+	}
+	if quote {
+		return QuoteValue(v)
+	}
+	return valueString(v)
+}
@@ -0,0 +1,107 @@
+package getters
+
+import (
+	"math/rand"
+	"regexp"
+	"testing"
+	"time"
+
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestRandomUUID(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	SetReferenceTime(time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC))
+	defer SetReferenceTime(time.Now())
+
+	for _, version := range []int{1, 4, 7} {
+		re := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-` + string(rune('0'+version)) + `[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+		g := NewRandomUUID("f1", version, false, rnd)
+		seen := make(map[string]bool)
+		last := ""
+		for i := 0; i < 10000; i++ {
+			v := g.String()
+			tu.Assert(t, re.MatchString(v), "Invalid version %d UUID %s", version, v)
+			tu.Assert(t, !seen[v], "Duplicated UUID %s", v)
+			seen[v] = true
+			if version == 7 {
+				tu.Assert(t, v > last, "UUID %s is not greater than %s", v, last)
+			}
+			last = v
+		}
+	}
+
+	b := NewRandomUUID("f1", 4, true, rnd).Value().([]byte)
+	tu.Equals(t, 16, len(b))
+	tu.Equals(t, byte(0x40), b[6]&0xf0)
+}
+
+func TestRandomULID(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	SetReferenceTime(time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC))
+	defer SetReferenceTime(time.Now())
+
+	re := regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`)
+	g := NewRandomULID("f1", rnd)
+	last := ""
+	for i := 0; i < 10000; i++ {
+		v := g.String()
+		tu.Assert(t, re.MatchString(v), "Invalid ULID %s", v)
+		tu.Assert(t, v > last, "ULID %s is not greater than %s", v, last)
+		last = v
+	}
+	// The first 10 chars are the timestamp
+	tu.Equals(t, "01E9PJV200", last[:10])
+}
+
+func TestCompositeKey(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	tenants := NewRandomEnum([]string{"a", "b", "c"}, false, rnd)
+	key := NewCompositeKey([]Getter{tenants}, map[string]int64{PrefixKey([]interface{}{"b"}): 10})
+	parts := key.Parts()
+
+	counters := map[string]int64{"b": 10}
+	for i := 0; i < 100; i++ {
+		// The counter part is used first, like when it is the first field of the table
+		id := parts[1].Value().(int64)
+		tenant := parts[0].Value().(string)
+		counters[tenant]++
+		tu.Equals(t, counters[tenant], id)
+	}
+}
+
+func TestSequence(t *testing.T) {
+	s := NewSequence("id", -1)
+	tu.Equals(t, int64(-1), s.Value())
+	tu.Equals(t, "0", s.String())
+
+	u := NewUintSequence("id", 1<<63)
+	tu.Equals(t, uint64(1<<63), u.Value())
+	tu.Equals(t, "9223372036854775809", u.Quote())
+}
+
+func TestPrefixValue(t *testing.T) {
+	date := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	datetime := time.Date(2020, 1, 2, 10, 20, 30, 500000000, time.UTC)
+	tests := []struct {
+		dataType  string
+		read      interface{}
+		generated interface{}
+	}{
+		{"date", []byte("2020-01-02"), date},
+		{"datetime", []byte("2020-01-02 10:20:30.500"), datetime},
+		{"timestamp", []byte("2020-01-02 10:20:30.5"), datetime},
+		{"time", []byte("-10:20:30"), -(10*time.Hour + 20*time.Minute + 30*time.Second)},
+		{"int", []byte("-5"), int64(-5)},
+		{"bigint", []byte("18446744073709551615"), uint64(1<<64 - 1)},
+		{"year", []byte("2020"), int64(2020)},
+		{"double", []byte("1.5"), 1.5},
+		{"bit", []byte{1, 2}, uint64(258)},
+		{"varchar", []byte("a"), "a"},
+		{"int", int64(7), int64(7)},
+	}
+	for _, test := range tests {
+		tu.Equals(t, PrefixKey([]interface{}{test.generated}),
+			PrefixKey([]interface{}{PrefixValue(test.dataType, test.read)}))
+	}
+}
@@ -0,0 +1,101 @@
+package getters
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// noBackslashEscapes must be true if the server's sql_mode has NO_BACKSLASH_ESCAPES.
+// In that mode, backslash is an ordinary character in string literals and the only
+// way to escape a quote is doubling it.
+var noBackslashEscapes bool
+
+// SetSQLMode sets the sql_mode used to quote values
+func SetSQLMode(sqlMode string) {
+	noBackslashEscapes = false
+	for _, mode := range strings.Split(strings.ToUpper(sqlMode), ",") {
+		if strings.TrimSpace(mode) == "NO_BACKSLASH_ESCAPES" {
+			noBackslashEscapes = true
+		}
+	}
+}
+
+// QuoteString returns s as a MySQL string literal, escaped according to the sql_mode.
+// Only ASCII bytes are escaped so multi-byte UTF-8 characters are kept as they are.
+func QuoteString(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+	b.WriteByte('\'')
+
+	if noBackslashEscapes {
+		b.WriteString(strings.Replace(s, "'", "''", -1))
+		b.WriteByte('\'')
+		return b.String()
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case 0:
+			b.WriteString(`\0`)
+		case '\'':
+			b.WriteString(`\'`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case 26:
+			b.WriteString(`\Z`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// QuoteBytes returns b as a MySQL hexadecimal literal. Hex literals don't depend on the
+// sql_mode or on the connection character set so any byte sequence can be used.
+func QuoteBytes(b []byte) string {
+	if len(b) == 0 {
+		return "''"
+	}
+	return "0x" + hex.EncodeToString(b)
+}
+
+// QuoteValue returns v quoted according to its type
+func QuoteValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return NULL
+	case string:
+		return QuoteString(val)
+	case []byte:
+		return QuoteBytes(val)
+	case time.Time:
+		return QuoteString(valueString(val))
+	default:
+		return valueString(val)
+	}
+}
+
+// valueString returns the unquoted string representation of v
+func valueString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return NULL
+	case []byte:
+		return string(val)
+	case time.Time:
+		// Fractional seconds are kept so samples from datetime(n) fields match the referenced values
+		return val.Format("2006-01-02 15:04:05.999999")
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
@@ -0,0 +1,37 @@
+package getters
+
+import (
+	"testing"
+	"time"
+
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestQuoteString(t *testing.T) {
+	defer SetSQLMode("")
+	tests := []struct {
+		sqlMode string
+		in      string
+		want    string
+	}{
+		{"", "it's", `'it\'s'`},
+		{"", "a\\b\"c\x00\n\r\x1a", `'a\\b\"c\0\n\r\Z'`},
+		{"", "Ñandú 日本語 🐬", "'Ñandú 日本語 🐬'"},
+		{"STRICT_TRANS_TABLES,NO_BACKSLASH_ESCAPES", `it's a\b`, `'it''s a\b'`},
+		{"NO_BACKSLASH_ESCAPES", "Ñandú's", "'Ñandú''s'"},
+	}
+
+	for _, test := range tests {
+		SetSQLMode(test.sqlMode)
+		tu.Equals(t, test.want, QuoteString(test.in))
+	}
+}
+
+func TestQuoteValue(t *testing.T) {
+	tu.Equals(t, NULL, QuoteValue(nil))
+	tu.Equals(t, "0x00ff41", QuoteValue([]byte{0, 255, 'A'}))
+	tu.Equals(t, "''", QuoteValue([]byte{}))
+	tu.Equals(t, "123", QuoteValue(int64(123)))
+	tu.Equals(t, "'2020-01-02 03:04:05'", QuoteValue(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)))
+	tu.Equals(t, "'2020-01-02 03:04:05.12'", QuoteValue(time.Date(2020, 1, 2, 3, 4, 5, 120000000, time.UTC)))
+}
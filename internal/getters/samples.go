@@ -1,21 +1,19 @@
 package getters
 
-import (
-	"fmt"
-	"math/rand"
-)
+import "math/rand"
 
 type RandomSample struct {
 	name      string
 	samples   []interface{}
 	allowNull bool
+	rnd       *rand.Rand
 }
 
 func (r *RandomSample) Value() interface{} {
-	if r.allowNull && rand.Int63n(100) < nilFrequency {
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
 		return nil
 	}
-	pos := rand.Int63n(int64(len(r.samples)))
+	pos := r.rnd.Int63n(int64(len(r.samples)))
 	return r.samples[pos]
 }
 
@@ -24,7 +22,7 @@ func (r *RandomSample) String() string {
 	if v == nil {
 		return NULL
 	}
-	return fmt.Sprintf("%v", v)
+	return valueString(v)
 }
 
 func (r *RandomSample) Quote() string {
@@ -32,15 +30,10 @@ func (r *RandomSample) Quote() string {
 	if v == nil {
 		return NULL
 	}
-	switch v.(type) {
-	case string:
-		return fmt.Sprintf("%q", v)
-	default:
-		return fmt.Sprintf("%v", v)
-	}
+	return QuoteValue(v)
 }
 
-func NewRandomSample(name string, samples []interface{}, allowNull bool) *RandomSample {
-	r := &RandomSample{name, samples, allowNull}
+func NewRandomSample(name string, samples []interface{}, allowNull bool, rnd *rand.Rand) *RandomSample {
+	r := &RandomSample{name, samples, allowNull, rnd}
 	return r
 }
@@ -0,0 +1,67 @@
+package getters
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// RandomSet getter. Generates values for set fields having a random number of members
+type RandomSet struct {
+	name       string
+	members    []string
+	minMembers int64
+	maxMembers int64
+	allowNull  bool
+	rnd        *rand.Rand
+}
+
+// Value returns a random subset of the members as a comma separated string. The number of members
+// is uniformly distributed between minMembers and maxMembers and members are in the definition order.
+// The empty set is an empty string.
+func (r *RandomSet) Value() interface{} {
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
+		return nil
+	}
+	count := r.minMembers + r.rnd.Int63n(r.maxMembers-r.minMembers+1)
+	// Selection sampling: each member is selected with probability needed/remaining
+	values := make([]string, 0, count)
+	for i, member := range r.members {
+		remaining := int64(len(r.members) - i)
+		if r.rnd.Int63n(remaining) < count-int64(len(values)) {
+			values = append(values, member)
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+func (r *RandomSet) String() string {
+	v := r.Value()
+	if v == nil {
+		return NULL
+	}
+	return v.(string)
+}
+
+func (r *RandomSet) Quote() string {
+	v := r.Value()
+	if v == nil {
+		return NULL
+	}
+	return QuoteString(v.(string))
+}
+
+// NewRandomSet returns a getter for set fields. Values have between minMembers and maxMembers
+// members, which are clamped to the [0, len(members)] range.
+func NewRandomSet(name string, members []string, minMembers, maxMembers int64, allowNull bool,
+	rnd *rand.Rand) *RandomSet {
+	if maxMembers > int64(len(members)) {
+		maxMembers = int64(len(members))
+	}
+	if minMembers < 0 {
+		minMembers = 0
+	}
+	if minMembers > maxMembers {
+		minMembers = maxMembers
+	}
+	return &RandomSet{name, members, minMembers, maxMembers, allowNull, rnd}
+}
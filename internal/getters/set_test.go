@@ -0,0 +1,43 @@
+package getters
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestRandomSet(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	members := []string{"a", "b", "c", "d"}
+
+	r := NewRandomSet("f1", members, 0, 4, false, rnd)
+	counts := make(map[int]int)
+	for i := 0; i < 1000; i++ {
+		v := r.Value().(string)
+		n := 0
+		if v != "" {
+			n = len(strings.Split(v, ","))
+			for _, member := range strings.Split(v, ",") {
+				tu.Assert(t, strings.Contains("abcd", member) && len(member) == 1, "Invalid set value %q", v)
+			}
+		}
+		counts[n]++
+	}
+	for n := 0; n <= 4; n++ {
+		tu.Assert(t, counts[n] > 100, "Only %d values having %d members", counts[n], n)
+	}
+
+	r = NewRandomSet("f1", members, 2, 3, false, rnd)
+	for i := 0; i < 100; i++ {
+		v := r.Value().(string)
+		n := len(strings.Split(v, ","))
+		tu.Assert(t, n >= 2 && n <= 3, "Invalid number of members in %q", v)
+	}
+
+	// Members are in the definition order
+	r = NewRandomSet("f1", members, 4, 10, false, rnd)
+	tu.Equals(t, "a,b,c,d", r.Value())
+	tu.Equals(t, "'a,b,c,d'", r.Quote())
+}
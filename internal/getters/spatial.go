@@ -0,0 +1,272 @@
+package getters
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxGeometryPoints = 10
+	maxGeometryParts  = 4
+	// Geometries are placed in a box having up to this fraction of the bounding box size
+	geometrySizeRatio = 0.1
+)
+
+// wkbTypes holds the WKB geometry type codes
+var wkbTypes = map[string]uint32{
+	"point":              1,
+	"linestring":         2,
+	"polygon":            3,
+	"multipoint":         4,
+	"multilinestring":    5,
+	"multipolygon":       6,
+	"geometrycollection": 7,
+}
+
+// BoundingBox holds the area where the coordinates of the generated geometries are placed
+type BoundingBox struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+// DefaultBoundingBox covers all the valid longitudes and latitudes so it can be used for
+// geographic and cartesian spatial reference systems
+var DefaultBoundingBox = BoundingBox{-180, -90, 180, 90}
+
+// ParseBoundingBox parses a bounding box in the form minX,minY,maxX,maxY
+func ParseBoundingBox(s string) (BoundingBox, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return BoundingBox{}, fmt.Errorf("invalid bounding box %q. It must be in the form minX,minY,maxX,maxY", s)
+	}
+	var coords [4]float64
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return BoundingBox{}, fmt.Errorf("invalid bounding box %q: %s", s, err)
+		}
+		coords[i] = v
+	}
+	bbox := BoundingBox{coords[0], coords[1], coords[2], coords[3]}
+	if !bbox.IsValid() {
+		return BoundingBox{}, fmt.Errorf("invalid bounding box %q: min values must be lower than max values", s)
+	}
+	return bbox, nil
+}
+
+// IsValid returns true if the bounding box has a non empty area
+func (b BoundingBox) IsValid() bool {
+	return b.MinX < b.MaxX && b.MinY < b.MaxY
+}
+
+type point struct {
+	x, y float64
+}
+
+// geometry holds a generated geometry. Points are used by point, linestring and polygon geometries
+// (polygons have a single closed ring) and parts are used by multi geometries and collections
+type geometry struct {
+	kind   string
+	points []point
+	parts  []geometry
+}
+
+// RandomGeometry getter. Generates valid geometries of the given type having all the coordinates
+// inside the bounding box. Polygons are star shaped so they are always closed and simple.
+type RandomGeometry struct {
+	name      string
+	kind      string
+	srid      int64
+	bbox      BoundingBox
+	allowNull bool
+	rnd       *rand.Rand
+}
+
+// Value returns the geometry in WKT format
+func (r *RandomGeometry) Value() interface{} {
+	g := r.geometry()
+	if g == nil {
+		return nil
+	}
+	return g.wkt()
+}
+
+// String returns the geometry in MySQL internal format (SRID + WKB), the format used by LOAD DATA
+func (r *RandomGeometry) String() string {
+	g := r.geometry()
+	if g == nil {
+		return NULL
+	}
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, uint32(r.srid))
+	g.wkb(buf)
+	return buf.String()
+}
+
+// Quote returns the geometry as an ST_GeomFromText call having the WKT and the SRID.
+// Coordinates are always written as x = longitude, y = latitude, also for geographic SRSs.
+func (r *RandomGeometry) Quote() string {
+	g := r.geometry()
+	if g == nil {
+		return NULL
+	}
+	if r.srid == 0 {
+		return fmt.Sprintf("ST_GeomFromText(%s)", QuoteString(g.wkt()))
+	}
+	return fmt.Sprintf("ST_GeomFromText(%s, %d, 'axis-order=long-lat')", QuoteString(g.wkt()), r.srid)
+}
+
+func (r *RandomGeometry) geometry() *geometry {
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
+		return nil
+	}
+	g := r.randomGeometry(r.kind, r.bbox)
+	return &g
+}
+
+func (r *RandomGeometry) randomGeometry(kind string, bbox BoundingBox) geometry {
+	switch kind {
+	case "point":
+		return geometry{kind: kind, points: []point{r.randomPoint(bbox)}}
+	case "linestring":
+		box := r.randomBox(bbox)
+		points := make([]point, 2+r.rnd.Intn(maxGeometryPoints-1))
+		for i := range points {
+			points[i] = r.randomPoint(box)
+		}
+		return geometry{kind: kind, points: points}
+	case "polygon":
+		return geometry{kind: kind, points: r.randomRing(r.randomBox(bbox))}
+	case "multipoint", "multilinestring", "multipolygon":
+		count := 1 + r.rnd.Intn(maxGeometryParts)
+		g := geometry{kind: kind, parts: make([]geometry, count)}
+		// Polygons in a multipolygon cannot overlap so each one is placed in its own strip
+		// of the bounding box
+		width := (bbox.MaxX - bbox.MinX) / float64(count)
+		for i := range g.parts {
+			box := bbox
+			if kind == "multipolygon" {
+				box.MinX = bbox.MinX + width*float64(i)
+				box.MaxX = box.MinX + width
+			}
+			g.parts[i] = r.randomGeometry(strings.TrimPrefix(kind, "multi"), box)
+		}
+		return g
+	case "geometrycollection", "geomcollection":
+		kinds := []string{"point", "linestring", "polygon"}
+		g := geometry{kind: "geometrycollection", parts: make([]geometry, 1+r.rnd.Intn(maxGeometryParts))}
+		for i := range g.parts {
+			g.parts[i] = r.randomGeometry(kinds[r.rnd.Intn(len(kinds))], bbox)
+		}
+		return g
+	default: // geometry fields accept any geometry type
+		kinds := []string{"point", "linestring", "polygon"}
+		return r.randomGeometry(kinds[r.rnd.Intn(len(kinds))], bbox)
+	}
+}
+
+func (r *RandomGeometry) randomPoint(bbox BoundingBox) point {
+	return point{
+		x: bbox.MinX + r.rnd.Float64()*(bbox.MaxX-bbox.MinX),
+		y: bbox.MinY + r.rnd.Float64()*(bbox.MaxY-bbox.MinY),
+	}
+}
+
+// randomBox returns a random box inside bbox, having up to geometrySizeRatio of its size
+func (r *RandomGeometry) randomBox(bbox BoundingBox) BoundingBox {
+	width := (bbox.MaxX - bbox.MinX) * geometrySizeRatio
+	height := (bbox.MaxY - bbox.MinY) * geometrySizeRatio
+	box := BoundingBox{MinX: bbox.MinX, MinY: bbox.MinY, MaxX: bbox.MaxX - width, MaxY: bbox.MaxY - height}
+	p := r.randomPoint(box)
+	return BoundingBox{p.x, p.y, p.x + width, p.y + height}
+}
+
+// randomRing returns a closed ring of points around the center of the box. Each vertex has its own
+// angular sector, so vertices are sorted by angle and the ring cannot intersect itself.
+// Sorting the vertices by angle also makes the ring counterclockwise.
+func (r *RandomGeometry) randomRing(bbox BoundingBox) []point {
+	cx, cy := (bbox.MinX+bbox.MaxX)/2, (bbox.MinY+bbox.MaxY)/2
+	rx, ry := (bbox.MaxX-bbox.MinX)/2, (bbox.MaxY-bbox.MinY)/2
+	count := 3 + r.rnd.Intn(maxGeometryPoints-2)
+	sector := 2 * math.Pi / float64(count)
+	points := make([]point, 0, count+1)
+	for i := 0; i < count; i++ {
+		angle := sector * (float64(i) + r.rnd.Float64())
+		// Keep the vertices away from the center to avoid degenerated polygons
+		radius := 0.5 + r.rnd.Float64()/2
+		points = append(points, point{cx + rx*radius*math.Cos(angle), cy + ry*radius*math.Sin(angle)})
+	}
+	return append(points, points[0])
+}
+
+func (g geometry) wkt() string {
+	return strings.ToUpper(g.kind) + g.wktBody()
+}
+
+func (g geometry) wktBody() string {
+	var parts []string
+	switch g.kind {
+	case "point", "linestring":
+		return "(" + formatPoints(g.points) + ")"
+	case "polygon":
+		return "((" + formatPoints(g.points) + "))"
+	case "geometrycollection":
+		for _, part := range g.parts {
+			parts = append(parts, part.wkt())
+		}
+	default:
+		for _, part := range g.parts {
+			parts = append(parts, part.wktBody())
+		}
+	}
+	return "(" + strings.Join(parts, ",") + ")"
+}
+
+func formatPoints(points []point) string {
+	coords := make([]string, 0, len(points))
+	for _, p := range points {
+		coords = append(coords, strconv.FormatFloat(p.x, 'f', 6, 64)+" "+strconv.FormatFloat(p.y, 'f', 6, 64))
+	}
+	return strings.Join(coords, ",")
+}
+
+// wkb writes the geometry in little endian WKB format
+func (g geometry) wkb(buf *bytes.Buffer) {
+	buf.WriteByte(1)
+	binary.Write(buf, binary.LittleEndian, wkbTypes[g.kind])
+	switch g.kind {
+	case "point":
+		binary.Write(buf, binary.LittleEndian, []float64{g.points[0].x, g.points[0].y})
+	case "linestring":
+		writePoints(buf, g.points)
+	case "polygon":
+		binary.Write(buf, binary.LittleEndian, uint32(1))
+		writePoints(buf, g.points)
+	default:
+		binary.Write(buf, binary.LittleEndian, uint32(len(g.parts)))
+		for _, part := range g.parts {
+			part.wkb(buf)
+		}
+	}
+}
+
+func writePoints(buf *bytes.Buffer, points []point) {
+	binary.Write(buf, binary.LittleEndian, uint32(len(points)))
+	for _, p := range points {
+		binary.Write(buf, binary.LittleEndian, []float64{p.x, p.y})
+	}
+}
+
+// NewRandomGeometry returns a getter for spatial fields. kind is the field data type: geometry, point,
+// linestring, polygon, multipoint, multilinestring, multipolygon or geometrycollection.
+// If the bounding box is not valid, DefaultBoundingBox is used.
+func NewRandomGeometry(name, kind string, srid int64, bbox BoundingBox, allowNull bool, rnd *rand.Rand) *RandomGeometry {
+	if !bbox.IsValid() {
+		bbox = DefaultBoundingBox
+	}
+	return &RandomGeometry{name, kind, srid, bbox, allowNull, rnd}
+}
@@ -0,0 +1,85 @@
+package getters
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestRandomGeometry(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	bbox := BoundingBox{10, 20, 30, 40}
+	coords := regexp.MustCompile(`(-?[\d.]+) (-?[\d.]+)`)
+
+	kinds := []string{"geometry", "point", "linestring", "polygon", "multipoint", "multilinestring",
+		"multipolygon", "geomcollection"}
+	for _, kind := range kinds {
+		r := NewRandomGeometry("f1", kind, 4326, bbox, false, rnd)
+		for i := 0; i < 100; i++ {
+			wkt := r.Value().(string)
+			if kind != "geometry" && kind != "geomcollection" {
+				tu.Assert(t, strings.HasPrefix(wkt, strings.ToUpper(kind)+"("), "Invalid %s WKT %s", kind, wkt)
+			}
+			for _, m := range coords.FindAllStringSubmatch(wkt, -1) {
+				x, _ := strconv.ParseFloat(m[1], 64)
+				y, _ := strconv.ParseFloat(m[2], 64)
+				tu.Assert(t, x >= 10 && x <= 30 && y >= 20 && y <= 40, "Point (%v %v) is outside the bounding box", x, y)
+			}
+		}
+	}
+
+	r := NewRandomGeometry("f1", "polygon", 4326, bbox, false, rnd)
+	tu.Assert(t, strings.HasPrefix(r.Quote(), "ST_GeomFromText('POLYGON(("), "Invalid quoted value %s", r.Quote())
+	tu.Assert(t, strings.HasSuffix(r.Quote(), ", 4326, 'axis-order=long-lat')"), "Invalid quoted value %s", r.Quote())
+
+	// Internal format: SRID + WKB
+	r = NewRandomGeometry("f1", "point", 4326, bbox, false, rnd)
+	v := []byte(r.String())
+	tu.Equals(t, 4+1+4+16, len(v))
+	tu.Equals(t, uint32(4326), binary.LittleEndian.Uint32(v))
+	tu.Equals(t, uint32(1), binary.LittleEndian.Uint32(v[5:]))
+}
+
+func TestRandomPolygonIsSimple(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	r := NewRandomGeometry("f1", "polygon", 0, DefaultBoundingBox, false, rnd)
+	for i := 0; i < 100; i++ {
+		ring := r.randomGeometry("polygon", DefaultBoundingBox).points
+		tu.Assert(t, len(ring) >= 4, "A polygon needs at least 4 points. Have %d", len(ring))
+		tu.Equals(t, ring[0], ring[len(ring)-1])
+		for j := 0; j < len(ring)-1; j++ {
+			for k := j + 2; k < len(ring)-1; k++ {
+				if j == 0 && k == len(ring)-2 {
+					continue // adjacent segments
+				}
+				tu.Assert(t, !segmentsIntersect(ring[j], ring[j+1], ring[k], ring[k+1]),
+					"The polygon intersects itself: %v", ring)
+			}
+		}
+	}
+}
+
+func TestParseBoundingBox(t *testing.T) {
+	bbox, err := ParseBoundingBox("-10, -20.5,10,20.5")
+	tu.Ok(t, err)
+	tu.Equals(t, BoundingBox{-10, -20.5, 10, 20.5}, bbox)
+
+	_, err = ParseBoundingBox("10,20,0,30")
+	tu.NotOk(t, err)
+	_, err = ParseBoundingBox("1,2,3")
+	tu.NotOk(t, err)
+}
+
+func segmentsIntersect(p1, p2, p3, p4 point) bool {
+	cross := func(a, b, c point) float64 {
+		return (b.x-a.x)*(c.y-a.y) - (b.y-a.y)*(c.x-a.x)
+	}
+	d1, d2 := cross(p3, p4, p1), cross(p3, p4, p2)
+	d3, d4 := cross(p1, p2, p3), cross(p1, p2, p4)
+	return d1*d2 < 0 && d3*d4 < 0
+}
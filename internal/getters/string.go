@@ -1,39 +1,60 @@
 package getters
 
 import (
-	"fmt"
 	"math/rand"
-
-	"github.com/icrowley/fake"
+	"strings"
 )
 
+// IsStringType returns true for the char, text, binary and blob types
+func IsStringType(dataType string) bool {
+	switch dataType {
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext", "binary", "varbinary",
+		"tinyblob", "blob", "mediumblob", "longblob":
+		return true
+	}
+	return false
+}
+
 // RandomString getter
 type RandomString struct {
 	name      string
+	minSize   int64
 	maxSize   int64
 	allowNull bool
+	rnd       *rand.Rand
 }
 
 func (r *RandomString) Value() interface{} {
-	if r.allowNull && rand.Int63n(100) < nilFrequency {
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
 		return nil
 	}
 	var s string
 	maxSize := uint64(r.maxSize)
 	if maxSize == 0 {
-		maxSize = uint64(rand.Int63n(100))
+		maxSize = uint64(r.rnd.Int63n(100))
+	}
+	if maxSize < uint64(r.minSize) {
+		maxSize = uint64(r.minSize)
 	}
 
 	if maxSize <= 10 {
-		s = fake.FirstName()
+		s = randomFirstName(r.rnd)
 	} else if maxSize < 30 {
-		s = fake.FullName()
+		s = randomFullName(r.rnd)
 	} else {
-		s = fake.Sentence()
+		s = randomSentence(r.rnd)
+	}
+	// Strings shorter than minSize are padded with more words
+	for int64(len(s)) < r.minSize {
+		s += " " + fakeValue(r.rnd, "words")
 	}
 	if len(s) > int(maxSize) {
 		s = s[:int(maxSize)]
 	}
+	// char fields remove trailing spaces, so they would count for the min size
+	if r.minSize > 0 && strings.HasSuffix(s, " ") {
+		s = s[:len(s)-1] + "x"
+	}
 	return s
 }
 
@@ -50,9 +71,17 @@ func (r *RandomString) Quote() string {
 	if v == nil {
 		return NULL
 	}
-	return fmt.Sprintf("%q", v)
+	return QuoteString(v.(string))
 }
 
-func NewRandomString(name string, maxSize int64, allowNull bool) *RandomString {
-	return &RandomString{name, maxSize, allowNull}
+func NewRandomString(name string, maxSize int64, allowNull bool, rnd *rand.Rand) *RandomString {
+	return &RandomString{name, 0, maxSize, allowNull, rnd}
+}
+
+// NewRandomStringRange returns a getter for strings having between minSize and maxSize chars
+func NewRandomStringRange(name string, minSize, maxSize int64, allowNull bool, rnd *rand.Rand) *RandomString {
+	if minSize > maxSize {
+		minSize = maxSize
+	}
+	return &RandomString{name, minSize, maxSize, allowNull, rnd}
 }
@@ -0,0 +1,202 @@
+package getters
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TemporalRange holds the range of values for date, datetime, timestamp and year getters
+type TemporalRange struct {
+	Min time.Time
+	Max time.Time
+	// Recent is the ratio (0 ~ 1) of values generated between the reference time - 1 year
+	// and the reference time, to skew the values toward recent dates
+	Recent float64
+}
+
+// temporalRanges holds the legal range of values for each temporal type
+var temporalRanges = map[string][2]time.Time{
+	"date":      {time.Date(1000, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(9999, 12, 31, 23, 59, 59, 999999999, time.UTC)},
+	"datetime":  {time.Date(1000, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(9999, 12, 31, 23, 59, 59, 999999000, time.UTC)},
+	"timestamp": {time.Date(1970, 1, 1, 0, 0, 1, 0, time.UTC), time.Date(2038, 1, 19, 3, 14, 7, 999999000, time.UTC)},
+	"year":      {time.Date(1901, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2155, 12, 31, 23, 59, 59, 999999999, time.UTC)},
+}
+
+// time fields range: -838:59:59 ~ 838:59:59
+const maxTimeValue = 838*time.Hour + 59*time.Minute + 59*time.Second
+
+// DefaultTemporalRange returns the legal range of values for a date, datetime, timestamp or year type.
+// Unknown types get the datetime range.
+func DefaultTemporalRange(dataType string) TemporalRange {
+	r, ok := temporalRanges[dataType]
+	if !ok {
+		r = temporalRanges["datetime"]
+	}
+	return TemporalRange{Min: r[0], Max: r[1]}
+}
+
+// DefaultTimeRange returns the legal range of values for time fields
+func DefaultTimeRange() (time.Duration, time.Duration) {
+	return -maxTimeValue, maxTimeValue
+}
+
+// ParseTemporalRange parses a range in the form min,max for a date, datetime, timestamp or year type.
+// Bounds are dates (YYYY-MM-DD), datetimes (YYYY-MM-DD HH:MM:SS[.ffffff]), years (YYYY) or relative
+// dates like -90d, in UTC. The range must be inside the legal range of the type.
+func ParseTemporalRange(dataType, s string) (TemporalRange, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return TemporalRange{}, fmt.Errorf("invalid %s range %q. It must be in the form min,max", dataType, s)
+	}
+	return ParseTemporalBounds(dataType, parts[0], parts[1])
+}
+
+// ParseTemporalBounds returns the range between min and max for a date, datetime, timestamp or year type.
+// Bounds are parsed using ParseDateTime. The range must be inside the legal range of the type.
+func ParseTemporalBounds(dataType, minValue, maxValue string) (TemporalRange, error) {
+	min, err := ParseDateTime(minValue)
+	if err != nil {
+		return TemporalRange{}, err
+	}
+	max, err := ParseDateTime(maxValue)
+	if err != nil {
+		return TemporalRange{}, err
+	}
+	// The max date or year includes all its seconds
+	switch dataType {
+	case "date":
+		max = max.Truncate(24 * time.Hour).Add(24*time.Hour - time.Nanosecond)
+	case "year":
+		max = time.Date(max.Year(), 12, 31, 23, 59, 59, 999999999, time.UTC)
+	}
+	legal := DefaultTemporalRange(dataType)
+	if min.After(max) {
+		return TemporalRange{}, fmt.Errorf("invalid %s range %s,%s: min is greater than max", dataType, minValue, maxValue)
+	}
+	if min.Before(legal.Min) || max.After(legal.Max) {
+		return TemporalRange{}, fmt.Errorf("invalid %s range %s,%s: the %s range is %s ~ %s", dataType, minValue, maxValue,
+			dataType, formatDateTime(legal.Min, 0), formatDateTime(legal.Max, 0))
+	}
+	return TemporalRange{Min: min, Max: max}, nil
+}
+
+// ParseDateTime parses a date (YYYY-MM-DD), a datetime (YYYY-MM-DD HH:MM:SS[.ffffff]) or a year (YYYY) in UTC.
+// It also accepts dates relative to the reference time: "now" or [+-]N followed by a unit (s, m, h, d, w or y),
+// like -90d or +1y.
+func ParseDateTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, ok := parseRelativeDateTime(s); ok {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02", "2006"} {
+		if t, err := time.ParseInLocation(layout, s, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q. Dates must be in the form YYYY-MM-DD, YYYY-MM-DD HH:MM:SS, YYYY"+
+		" or relative to now, like -90d", s)
+}
+
+// parseRelativeDateTime parses "now" or an offset from the reference time like -90d
+func parseRelativeDateTime(s string) (time.Time, bool) {
+	ref := now().UTC()
+	if strings.ToLower(s) == "now" {
+		return ref, true
+	}
+	if len(s) < 3 || (s[0] != '-' && s[0] != '+') {
+		return time.Time{}, false
+	}
+	n, err := strconv.Atoi(s[1 : len(s)-1])
+	if err != nil || n < 0 {
+		return time.Time{}, false
+	}
+	if s[0] == '-' {
+		n = -n
+	}
+	switch s[len(s)-1] {
+	case 's':
+		return ref.Add(time.Duration(n) * time.Second), true
+	case 'm':
+		return ref.Add(time.Duration(n) * time.Minute), true
+	case 'h':
+		return ref.Add(time.Duration(n) * time.Hour), true
+	case 'd':
+		return ref.AddDate(0, 0, n), true
+	case 'w':
+		return ref.AddDate(0, 0, 7*n), true
+	case 'y':
+		return ref.AddDate(n, 0, 0), true
+	}
+	return time.Time{}, false
+}
+
+// ParseTimeRange parses a range in the form min,max for time fields. Bounds are in the form [-]HHH:MM:SS[.ffffff]
+func ParseTimeRange(s string) (time.Duration, time.Duration, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid time range %q. It must be in the form min,max", s)
+	}
+	min, err := parseTime(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	max, err := parseTime(parts[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	if min > max {
+		return 0, 0, fmt.Errorf("invalid time range %q: min is greater than max", s)
+	}
+	if min < -maxTimeValue || max > maxTimeValue {
+		return 0, 0, fmt.Errorf("invalid time range %q: the time range is -838:59:59 ~ 838:59:59", s)
+	}
+	return min, max, nil
+}
+
+func parseTime(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	sign := time.Duration(1)
+	if strings.HasPrefix(s, "-") {
+		sign, s = -1, s[1:]
+	}
+	var h, m int64
+	var sec float64
+	if n, err := fmt.Sscanf(s, "%d:%d:%f", &h, &m, &sec); err != nil || n != 3 || m > 59 || sec >= 60 {
+		return 0, fmt.Errorf("invalid time %q. Times must be in the form [-]HHH:MM:SS[.ffffff]", s)
+	}
+	d := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sec*float64(time.Second))
+	return sign * d.Round(time.Microsecond), nil
+}
+
+// random returns a random time in the range, truncated to a multiple of unit
+func (r TemporalRange) random(rnd *rand.Rand, unit time.Duration) time.Time {
+	min, max := r.Min, r.Max
+	if r.Recent > 0 && rnd.Float64() < r.Recent {
+		recentMin, recentMax := now().UTC().AddDate(-1, 0, 0), now().UTC()
+		if recentMin.After(min) {
+			min = recentMin
+		}
+		if recentMax.Before(max) {
+			max = recentMax
+		}
+		// The range doesn't include recent dates
+		if max.Before(min) {
+			min, max = r.Min, r.Max
+		}
+	}
+
+	// The legal ranges don't fit into a time.Duration (~292 years) so seconds and nanoseconds
+	// are generated separately
+	seconds := min.Unix() + int64(randomUint64(rnd, uint64(max.Unix()-min.Unix())))
+	t := time.Unix(seconds, rnd.Int63n(int64(time.Second))).UTC().Truncate(unit)
+	if t.Before(min) {
+		t = t.Add(unit)
+	}
+	if t.After(max) {
+		t = max.Truncate(unit)
+	}
+	return t
+}
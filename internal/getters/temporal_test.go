@@ -0,0 +1,98 @@
+package getters
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestParseTemporalRange(t *testing.T) {
+	r, err := ParseTemporalRange("date", "2019-01-01,2019-12-31")
+	tu.Ok(t, err)
+	tu.Equals(t, time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), r.Min)
+	tu.Equals(t, time.Date(2019, 12, 31, 23, 59, 59, 999999999, time.UTC), r.Max)
+
+	r, err = ParseTemporalRange("year", "2000,2010")
+	tu.Ok(t, err)
+	tu.Equals(t, time.Date(2010, 12, 31, 23, 59, 59, 999999999, time.UTC), r.Max)
+
+	_, err = ParseTemporalRange("timestamp", "1960-01-01,2000-01-01")
+	tu.NotOk(t, err)
+	_, err = ParseTemporalRange("datetime", "2020-01-01,2019-01-01")
+	tu.NotOk(t, err)
+	_, err = ParseTemporalRange("date", "2020-01-01")
+	tu.NotOk(t, err)
+}
+
+func TestParseTimeRange(t *testing.T) {
+	min, max, err := ParseTimeRange("-100:30:00,08:00:00.5")
+	tu.Ok(t, err)
+	tu.Equals(t, -(100*time.Hour + 30*time.Minute), min)
+	tu.Equals(t, 8*time.Hour+500*time.Millisecond, max)
+
+	_, _, err = ParseTimeRange("00:00:00,839:00:00")
+	tu.NotOk(t, err)
+	_, _, err = ParseTimeRange("10:00:00,09:00:00")
+	tu.NotOk(t, err)
+}
+
+func TestTemporalRangeValues(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	SetReferenceTime(time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC))
+	defer SetReferenceTime(time.Now())
+
+	r := DefaultTemporalRange("timestamp")
+	r.Recent = 0.5
+	ts := NewRandomDateTime("f1", 6, r, false, rnd)
+	recent := 0
+	for i := 0; i < 1000; i++ {
+		v := ts.Value().(time.Time)
+		tu.Assert(t, !v.Before(r.Min) && !v.After(r.Max), "timestamp %s out of range", v)
+		if v.Year() >= 2019 && v.Before(now()) {
+			recent++
+		}
+	}
+	tu.Assert(t, recent > 400, "Only %d recent values", recent)
+
+	y := NewRandomYear("f1", DefaultTemporalRange("year"), false, rnd)
+	for i := 0; i < 1000; i++ {
+		v := y.Value().(int64)
+		tu.Assert(t, v >= 1901 && v <= 2155, "year %d out of range", v)
+	}
+
+	r, err := ParseTemporalRange("date", "2019-02-01,2019-02-03")
+	tu.Ok(t, err)
+	d := NewRandomDate("f1", r, false, rnd)
+	for i := 0; i < 100; i++ {
+		v := d.String()
+		tu.Assert(t, v >= "2019-02-01" && v <= "2019-02-03", "date %s out of range", v)
+	}
+
+	min, max, err := ParseTimeRange("-10:00:00,-09:00:00")
+	tu.Ok(t, err)
+	tm := NewRandomTime(0, min, max, false, rnd)
+	for i := 0; i < 100; i++ {
+		v := tm.String()
+		tu.Assert(t, v >= "-09:00:00" && v <= "-10:00:00", "time %s out of range", v)
+	}
+}
+
+func TestParseRelativeDateTime(t *testing.T) {
+	SetReferenceTime(time.Date(2020, 6, 15, 12, 0, 0, 0, time.UTC))
+	defer SetReferenceTime(time.Now())
+
+	tests := map[string]time.Time{
+		"now":  time.Date(2020, 6, 15, 12, 0, 0, 0, time.UTC),
+		"-90d": time.Date(2020, 3, 17, 12, 0, 0, 0, time.UTC),
+		"+1y":  time.Date(2021, 6, 15, 12, 0, 0, 0, time.UTC),
+		"-2w":  time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC),
+		"-30m": time.Date(2020, 6, 15, 11, 30, 0, 0, time.UTC),
+	}
+	for s, want := range tests {
+		got, err := ParseDateTime(s)
+		tu.Ok(t, err)
+		tu.Equals(t, want, got)
+	}
+}
@@ -3,37 +3,67 @@ package getters
 import (
 	"fmt"
 	"math/rand"
+	"time"
 )
 
 // RandomTime Getter
 type RandomTime struct {
+	precision int64
+	min       time.Duration
+	max       time.Duration
 	allowNull bool
+	rnd       *rand.Rand
 }
 
+// Value returns a random time in the range as a string in the form [-]HH:MM:SS[.ffffff]
 func (r *RandomTime) Value() interface{} {
-	if r.allowNull && rand.Int63n(100) < nilFrequency {
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
 		return nil
 	}
-	h := rand.Int63n(24)
-	m := rand.Int63n(60)
-	s := rand.Int63n(60)
-	return fmt.Sprintf("%02d:%02d:%02d", h, m, s)
+	unit := fractionUnit(r.precision)
+	d := r.min + time.Duration(randomUint64(r.rnd, uint64(r.max-r.min)))
+	d -= d % unit
+	if d < r.min {
+		d += unit
+	}
+	if d > r.max {
+		d -= unit
+	}
+	return formatTime(d, r.precision)
 }
 
 func (r *RandomTime) String() string {
-	if r == nil {
+	v := r.Value()
+	if v == nil {
 		return NULL
 	}
-	return r.Value().(string)
+	return v.(string)
 }
 
 func (r *RandomTime) Quote() string {
-	if r == nil {
+	v := r.Value()
+	if v == nil {
 		return NULL
 	}
-	return fmt.Sprintf("%q", r.Value())
+	return QuoteString(v.(string))
+}
+
+// NewRandomTime returns a getter for time fields. precision is the number of fractional digits,
+// as in time(6), between 0 and 6. Use DefaultTimeRange() for the full range of the time type.
+func NewRandomTime(precision int64, min, max time.Duration, allowNull bool, rnd *rand.Rand) *RandomTime {
+	return &RandomTime{clampPrecision(precision), min, max, allowNull, rnd}
 }
 
-func NewRandomTime(allowNull bool) *RandomTime {
-	return &RandomTime{allowNull}
+// formatTime returns d formatted as [-]HH:MM:SS having precision fractional digits
+func formatTime(d time.Duration, precision int64) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	h, m, s := d/time.Hour, d%time.Hour/time.Minute, d%time.Minute/time.Second
+	if precision == 0 {
+		return fmt.Sprintf("%s%02d:%02d:%02d", sign, h, m, s)
+	}
+	fraction := d % time.Second / fractionUnit(precision)
+	return fmt.Sprintf("%s%02d:%02d:%02d.%0*d", sign, h, m, s, int(precision), fraction)
 }
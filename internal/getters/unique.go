@@ -0,0 +1,501 @@
+package getters
+
+import (
+	"math"
+	"math/bits"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// Domain maps the numbers in the [0, Size()) range to distinct values of a column type.
+// Domains are used to generate values for the columns in unique keys.
+type Domain interface {
+	// Size returns the number of distinct values in the domain, up to math.MaxUint64
+	Size() uint64
+	// Value returns the value for the number n, which must be lower than Size()
+	Value(n uint64) interface{}
+	// Format returns the value formatted for csv/tsv output or, if quote is true, as an SQL literal
+	Format(v interface{}, quote bool) string
+}
+
+// UniqueKey generates distinct tuples of values for the columns of a unique key.
+// The tuples are a permutation of the key's value space (the product of the domains sizes), so
+// there are no duplicated values until all the tuples have been generated.
+type UniqueKey struct {
+	domains []Domain
+	size    uint64
+	step    uint64
+	offset  uint64
+	count   uint64
+	row     uint64
+	current []uint64
+}
+
+// NewUniqueKey returns a new unique key having a column for each domain
+func NewUniqueKey(domains []Domain, rnd *rand.Rand) *UniqueKey {
+	size := uint64(1)
+	for _, d := range domains {
+		size = mulSaturated(size, d.Size())
+	}
+	k := &UniqueKey{domains: domains, size: size, current: make([]uint64, len(domains))}
+	if size > 1 {
+		// Any step coprime with the size generates all the numbers in [0, size) before repeating one
+		k.offset = randomUint64(rnd, size-1)
+		for k.step = 1 + randomUint64(rnd, size-2); gcd(k.step, size) != 1; k.step++ {
+		}
+	}
+	return k
+}
+
+// Size returns the number of distinct tuples the key can have
+func (k *UniqueKey) Size() uint64 {
+	return k.size
+}
+
+// Parts returns a getter for each column of the key, in the same order as the domains.
+// All the parts must be used once per row.
+func (k *UniqueKey) Parts() []Getter {
+	return keyParts(k, len(k.domains))
+}
+
+func (k *UniqueKey) rows() uint64 {
+	return k.row
+}
+
+// next generates the tuple for the next row
+func (k *UniqueKey) next() {
+	hi, lo := bits.Mul64(k.count, k.step)
+	n := addMod(bits.Rem64(hi, lo, k.size), k.offset, k.size)
+	k.count++
+	// Mixed radix decomposition of n using the domains sizes as the bases
+	for i := len(k.domains) - 1; i >= 0; i-- {
+		base := k.domains[i].Size()
+		k.current[i] = n % base
+		n /= base
+	}
+	k.row++
+}
+
+func (k *UniqueKey) value(pos int) interface{} {
+	return k.domains[pos].Value(k.current[pos])
+}
+
+func (k *UniqueKey) format(pos int, v interface{}, quote bool) string {
+	return k.domains[pos].Format(v, quote)
+}
+
+// maxDistinctTries is the number of tuples a DistinctKey generates looking for a tuple not generated yet
+const maxDistinctTries = 100
+
+// DistinctKey wraps the getters of the columns of a unique key that keep their own values, like profiled
+// columns, and skips the tuples already generated. Unlike UniqueKey, it has to remember the tuples, and it
+// returns a duplicated tuple if it cannot find a new one after maxDistinctTries tries.
+type DistinctKey struct {
+	getters  []Getter
+	prefixes []int64
+	seen     map[string]struct{}
+	row      uint64
+	current  []interface{}
+}
+
+// NewDistinctKey returns a new key having a column for each getter. prefixes holds the index prefix length of
+// each column, like 10 for KEY (name(10)), or 0 if the whole values are indexed.
+func NewDistinctKey(getters []Getter, prefixes []int64) *DistinctKey {
+	return &DistinctKey{getters: getters, prefixes: prefixes, seen: make(map[string]struct{}),
+		current: make([]interface{}, len(getters))}
+}
+
+// Parts returns a getter for each column of the key, in the same order as the getters.
+// All the parts must be used once per row.
+func (k *DistinctKey) Parts() []Getter {
+	return keyParts(k, len(k.getters))
+}
+
+func (k *DistinctKey) rows() uint64 {
+	return k.row
+}
+
+func (k *DistinctKey) next() {
+	for i := 0; i < maxDistinctTries; i++ {
+		for j, g := range k.getters {
+			k.current[j] = g.Value()
+		}
+		key, ok := k.key()
+		if !ok {
+			break
+		}
+		if _, seen := k.seen[key]; !seen {
+			k.seen[key] = struct{}{}
+			break
+		}
+	}
+	k.row++
+}
+
+// key returns the indexed part of the current tuple. Tuples having NULL values are never duplicated,
+// so it returns false for them
+func (k *DistinctKey) key() (string, bool) {
+	values := make([]interface{}, len(k.current))
+	for i, v := range k.current {
+		if v == nil {
+			return "", false
+		}
+		values[i] = v
+		if n := k.prefixes[i]; n > 0 {
+			switch val := v.(type) {
+			case string:
+				if r := []rune(val); int64(len(r)) > n {
+					values[i] = string(r[:n])
+				}
+			case []byte:
+				if int64(len(val)) > n {
+					val = val[:n]
+				}
+				values[i] = string(val)
+			}
+		}
+	}
+	return PrefixKey(values), true
+}
+
+func (k *DistinctKey) value(pos int) interface{} {
+	return k.current[pos]
+}
+
+func (k *DistinctKey) format(pos int, v interface{}, quote bool) string {
+	return FormatValue(k.getters[pos], v, quote)
+}
+
+// tupleGenerator is implemented by the keys generating the values for all their columns at once
+type tupleGenerator interface {
+	// rows returns the number of tuples generated
+	rows() uint64
+	// next generates the tuple for the next row
+	next()
+	value(pos int) interface{}
+	format(pos int, v interface{}, quote bool) string
+}
+
+func keyParts(key tupleGenerator, count int) []Getter {
+	parts := make([]Getter, 0, count)
+	for i := 0; i < count; i++ {
+		parts = append(parts, &KeyPart{key: key, pos: i})
+	}
+	return parts
+}
+
+// KeyPart getter. Generates the values for a column of a key
+type KeyPart struct {
+	key tupleGenerator
+	pos int
+	row uint64
+}
+
+// Value returns the value of the column for the current row. The key moves to the next tuple when
+// a part is used for a row the key didn't generate yet, so parts can be used in any order.
+func (r *KeyPart) Value() interface{} {
+	r.row++
+	if r.row > r.key.rows() {
+		r.key.next()
+	}
+	return r.key.value(r.pos)
+}
+
+func (r *KeyPart) String() string {
+	return r.key.format(r.pos, r.Value(), false)
+}
+
+func (r *KeyPart) Quote() string {
+	return r.key.format(r.pos, r.Value(), true)
+}
+
+// NewIntDomain returns a domain for integer values in the [min, max] range
+func NewIntDomain(min, max int64) Domain {
+	return &intDomain{min, uint64(max - min)}
+}
+
+type intDomain struct {
+	min   int64
+	delta uint64 // max - min
+}
+
+func (d *intDomain) Size() uint64 {
+	return addSaturated(d.delta, 1)
+}
+
+func (d *intDomain) Value(n uint64) interface{} {
+	return d.min + int64(n)
+}
+
+func (d *intDomain) Format(v interface{}, quote bool) string {
+	return strconv.FormatInt(v.(int64), 10)
+}
+
+// NewUintDomain returns a domain for unsigned integer values in the [min, max] range
+func NewUintDomain(min, max uint64) Domain {
+	return &uintDomain{min, max - min}
+}
+
+type uintDomain struct {
+	min   uint64
+	delta uint64
+}
+
+func (d *uintDomain) Size() uint64 {
+	return addSaturated(d.delta, 1)
+}
+
+func (d *uintDomain) Value(n uint64) interface{} {
+	return d.min + n
+}
+
+func (d *uintDomain) Format(v interface{}, quote bool) string {
+	return strconv.FormatUint(v.(uint64), 10)
+}
+
+// NewDecimalDomain returns a domain for non negative decimal(precision, scale) values
+func NewDecimalDomain(precision, scale int64) Domain {
+	return &decimalDomain{precision, scale}
+}
+
+type decimalDomain struct {
+	precision int64
+	scale     int64
+}
+
+func (d *decimalDomain) Size() uint64 {
+	size := uint64(1)
+	for i := int64(0); i < d.precision; i++ {
+		size = mulSaturated(size, 10)
+	}
+	return size
+}
+
+func (d *decimalDomain) Value(n uint64) interface{} {
+	s := strconv.FormatUint(n, 10)
+	if d.scale == 0 {
+		return s
+	}
+	for int64(len(s)) <= d.scale {
+		s = "0" + s
+	}
+	return s[:int64(len(s))-d.scale] + "." + s[int64(len(s))-d.scale:]
+}
+
+func (d *decimalDomain) Format(v interface{}, quote bool) string {
+	return v.(string)
+}
+
+// uniqueStringDigits are the digits used to build unique strings. Lowercase only, so values are
+// also distinct using case insensitive collations.
+const uniqueStringDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// NewStringDomain returns a domain for strings having up to maxLength chars
+func NewStringDomain(maxLength int64) Domain {
+	return &stringDomain{maxLength}
+}
+
+type stringDomain struct {
+	maxLength int64
+}
+
+func (d *stringDomain) Size() uint64 {
+	size := uint64(1)
+	for i := int64(0); i < d.maxLength; i++ {
+		size = mulSaturated(size, uint64(len(uniqueStringDigits)))
+	}
+	return size
+}
+
+// Value returns n written in base 36
+func (d *stringDomain) Value(n uint64) interface{} {
+	return strconv.FormatUint(n, len(uniqueStringDigits))
+}
+
+func (d *stringDomain) Format(v interface{}, quote bool) string {
+	if quote {
+		return QuoteString(v.(string))
+	}
+	return v.(string)
+}
+
+// NewEnumDomain returns a domain for enum values
+func NewEnumDomain(values []string) Domain {
+	return &enumDomain{values}
+}
+
+type enumDomain struct {
+	values []string
+}
+
+func (d *enumDomain) Size() uint64 {
+	return uint64(len(d.values))
+}
+
+func (d *enumDomain) Value(n uint64) interface{} {
+	return d.values[n]
+}
+
+func (d *enumDomain) Format(v interface{}, quote bool) string {
+	if quote {
+		return QuoteString(v.(string))
+	}
+	return v.(string)
+}
+
+// NewBitDomain returns a domain for bit(size) values. size must be between 1 and 64
+func NewBitDomain(size int64) Domain {
+	if size < 1 || size > 64 {
+		size = 64
+	}
+	return &bitDomain{size}
+}
+
+type bitDomain struct {
+	size int64
+}
+
+func (d *bitDomain) Size() uint64 {
+	return addSaturated(1<<uint(d.size)-1, 1)
+}
+
+func (d *bitDomain) Value(n uint64) interface{} {
+	return n
+}
+
+func (d *bitDomain) Format(v interface{}, quote bool) string {
+	if quote {
+		return "b'" + strconv.FormatUint(v.(uint64), 2) + "'"
+	}
+	return bitString(v.(uint64), d.size)
+}
+
+// NewDateTimeDomain returns a domain for date, datetime and timestamp values in the range. Values are
+// multiples of a day for dates and of the smallest fraction of a second allowed by precision for the
+// other types.
+func NewDateTimeDomain(dataType string, r TemporalRange, precision int64) Domain {
+	precision = clampPrecision(precision)
+	unit := fractionUnit(precision)
+	if dataType == "date" {
+		unit, precision = 24*time.Hour, -1
+	}
+	first := r.Min.Truncate(unit)
+	if first.Before(r.Min) {
+		first = first.Add(unit)
+	}
+	last := r.Max.Truncate(unit)
+	d := &dateTimeDomain{first: first, unit: unit, precision: precision}
+	if last.Before(first) {
+		return d
+	}
+	// The legal ranges don't fit into a time.Duration so seconds and fractions are counted separately
+	seconds := last.Unix() - first.Unix()
+	if unit > time.Second {
+		d.size = uint64(seconds/int64(unit/time.Second)) + 1
+		return d
+	}
+	perSecond := int64(time.Second / unit)
+	d.size = uint64(seconds*perSecond+int64(last.Nanosecond()-first.Nanosecond())/int64(unit)) + 1
+	return d
+}
+
+type dateTimeDomain struct {
+	first     time.Time
+	unit      time.Duration
+	precision int64 // -1 for dates
+	size      uint64
+}
+
+func (d *dateTimeDomain) Size() uint64 {
+	return d.size
+}
+
+func (d *dateTimeDomain) Value(n uint64) interface{} {
+	if d.unit > time.Second {
+		return d.first.AddDate(0, 0, int(n))
+	}
+	perSecond := uint64(time.Second / d.unit)
+	nsec := int64(d.first.Nanosecond()) + int64(n%perSecond)*int64(d.unit)
+	return time.Unix(d.first.Unix()+int64(n/perSecond), nsec).UTC()
+}
+
+func (d *dateTimeDomain) Format(v interface{}, quote bool) string {
+	s := v.(time.Time).Format("2006-01-02")
+	if d.precision >= 0 {
+		s = formatDateTime(v.(time.Time), d.precision)
+	}
+	if quote {
+		return QuoteString(s)
+	}
+	return s
+}
+
+// NewTimeDomain returns a domain for time values in the [min, max] range, being multiples
+// of the smallest fraction of a second allowed by precision
+func NewTimeDomain(min, max time.Duration, precision int64) Domain {
+	precision = clampPrecision(precision)
+	unit := fractionUnit(precision)
+	first := min - min%unit
+	if first < min {
+		first += unit
+	}
+	d := &timeDomain{first: first, unit: unit, precision: precision}
+	if max >= first {
+		d.size = uint64((max-first)/unit) + 1
+	}
+	return d
+}
+
+type timeDomain struct {
+	first     time.Duration
+	unit      time.Duration
+	precision int64
+	size      uint64
+}
+
+func (d *timeDomain) Size() uint64 {
+	return d.size
+}
+
+func (d *timeDomain) Value(n uint64) interface{} {
+	return formatTime(d.first+time.Duration(n)*d.unit, d.precision)
+}
+
+func (d *timeDomain) Format(v interface{}, quote bool) string {
+	if quote {
+		return QuoteString(v.(string))
+	}
+	return v.(string)
+}
+
+func mulSaturated(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	if hi != 0 {
+		return math.MaxUint64
+	}
+	return lo
+}
+
+func addSaturated(a, b uint64) uint64 {
+	sum, carry := bits.Add64(a, b, 0)
+	if carry != 0 {
+		return math.MaxUint64
+	}
+	return sum
+}
+
+// addMod returns (a + b) mod m for a, b < m, without overflows
+func addMod(a, b, m uint64) uint64 {
+	if a >= m-b {
+		return a - (m - b)
+	}
+	return a + b
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,66 @@
+package getters
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestUniqueKey(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	key := NewUniqueKey([]Domain{NewIntDomain(-5, 4), NewEnumDomain([]string{"a", "b", "c"})}, rnd)
+	tu.Equals(t, uint64(30), key.Size())
+
+	parts := key.Parts()
+	seen := make(map[string]bool)
+	for i := 0; i < 30; i++ {
+		// Parts can be used in any order
+		b := parts[1].Quote()
+		a := parts[0].Quote()
+		tu.Assert(t, !seen[a+b], "Duplicated value %s, %s", a, b)
+		seen[a+b] = true
+	}
+	tu.Assert(t, seen["-5'a'"] && seen["4'c'"], "Missing values in the key value space")
+}
+
+func TestDomains(t *testing.T) {
+	tests := []struct {
+		domain Domain
+		size   uint64
+		n      uint64
+		want   string
+	}{
+		{NewIntDomain(-128, 127), 256, 255, "127"},
+		{NewUintDomain(0, 1<<64-1), 1<<64 - 1, 1<<64 - 2, "18446744073709551614"},
+		{NewDecimalDomain(5, 2), 100000, 12345, "123.45"},
+		{NewDecimalDomain(5, 2), 100000, 5, "0.05"},
+		{NewStringDomain(2), 36 * 36, 36*36 - 1, "'zz'"},
+		{NewBitDomain(3), 8, 5, "b'101'"},
+		{NewDateTimeDomain("date", DefaultTemporalRange("date"), 0), 3287182, 3287181, "'9999-12-31'"},
+		{NewDateTimeDomain("timestamp", DefaultTemporalRange("timestamp"), 0), 2147483647, 0, "'1970-01-01 00:00:01'"},
+		{NewDateTimeDomain("datetime", TemporalRange{Min: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+			Max: time.Date(2019, 1, 1, 0, 0, 1, 0, time.UTC)}, 1), 11, 10, "'2019-01-01 00:00:01.0'"},
+		{NewTimeDomain(-time.Hour, time.Hour, 0), 7201, 0, "'-01:00:00'"},
+	}
+	for _, test := range tests {
+		tu.Equals(t, test.size, test.domain.Size())
+		tu.Equals(t, test.want, test.domain.Format(test.domain.Value(test.n), true))
+	}
+}
+
+func TestDistinctKey(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	// Only the first char is indexed, so "ab" and "ac" are duplicates
+	names := NewRandomSample("name", []interface{}{"ab", "ac", "b"}, false, rnd)
+	dates := NewRandomSample("day", []interface{}{"2020-01-01"}, false, rnd)
+	parts := NewDistinctKey([]Getter{names, dates}, []int64{1, 0}).Parts()
+	seen := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		prefix := parts[0].String()[:1]
+		tu.Assert(t, !seen[prefix], "Duplicated prefix %s", prefix)
+		seen[prefix] = true
+		tu.Equals(t, "'2020-01-01'", parts[1].Quote())
+	}
+}
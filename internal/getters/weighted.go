@@ -0,0 +1,67 @@
+package getters
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Weighted getter. Returns the values of one of its getters, chosen at random for each value
+// with a probability proportional to its weight
+type Weighted struct {
+	getters    []Getter
+	cumulative []float64
+	rnd        *rand.Rand
+	// last is the getter chosen for the last value, used to format it
+	last Getter
+}
+
+func (r *Weighted) choose() Getter {
+	total := r.cumulative[len(r.cumulative)-1]
+	x := r.rnd.Float64() * total
+	i := sort.Search(len(r.cumulative), func(i int) bool { return r.cumulative[i] > x })
+	if i == len(r.getters) {
+		i--
+	}
+	return r.getters[i]
+}
+
+func (r *Weighted) Value() interface{} {
+	r.last = r.choose()
+	return r.last.Value()
+}
+
+func (r *Weighted) String() string {
+	return r.choose().String()
+}
+
+func (r *Weighted) Quote() string {
+	return r.choose().Quote()
+}
+
+// Format formats a value like the getter that generated it
+func (r *Weighted) Format(v interface{}, quote bool) string {
+	if r.last == nil {
+		return FormatValue(r.getters[0], v, quote)
+	}
+	return FormatValue(r.last, v, quote)
+}
+
+// NewWeighted returns a getter choosing among getters with the probabilities given by weights, which
+// don't need to add up to 1. Getters having a weight <= 0 are never chosen. It returns nil if there
+// are no getters having a positive weight.
+func NewWeighted(getters []Getter, weights []float64, rnd *rand.Rand) *Weighted {
+	r := &Weighted{rnd: rnd}
+	total := 0.0
+	for i, g := range getters {
+		if i >= len(weights) || weights[i] <= 0 {
+			continue
+		}
+		total += weights[i]
+		r.getters = append(r.getters, g)
+		r.cumulative = append(r.cumulative, total)
+	}
+	if len(r.getters) == 0 {
+		return nil
+	}
+	return r
+}
@@ -0,0 +1,23 @@
+package getters
+
+import (
+	"math/rand"
+	"testing"
+
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestWeighted(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	g := NewWeighted([]Getter{NewConstant(int64(1)), NewConstant("a"), NewConstant(int64(3))},
+		[]float64{3, 1, 0}, rnd)
+	counts := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		counts[g.Quote()]++
+	}
+	tu.Equals(t, 2, len(counts))
+	tu.Assert(t, counts["1"] > 7000 && counts["1"] < 8000, "Invalid frequency %d for 1", counts["1"])
+	tu.Assert(t, counts["'a'"] > 2000 && counts["'a'"] < 3000, "Invalid frequency %d for 'a'", counts["'a'"])
+
+	tu.Assert(t, NewWeighted([]Getter{NewConstant(1)}, []float64{0}, rnd) == nil, "Expected nil getter")
+}
@@ -1,12 +1,41 @@
 package getters
 
-func NewRandomYear(name string, format int, allowNull bool) *RandomIntRange {
-	if format == 2 {
-		return &RandomIntRange{name, 01, 99, allowNull}
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// RandomYear getter. Generates values for year fields
+type RandomYear struct {
+	name      string
+	r         TemporalRange
+	allowNull bool
+	rnd       *rand.Rand
+}
+
+// Value returns a random year in the range as an int64
+func (r *RandomYear) Value() interface{} {
+	if r.allowNull && r.rnd.Int63n(100) < nilFrequency {
+		return nil
+	}
+	return int64(r.r.random(r.rnd, time.Second).Year())
+}
+
+func (r *RandomYear) String() string {
+	v := r.Value()
+	if v == nil {
+		return NULL
 	}
-	return &RandomIntRange{name, 1901, 2155, allowNull}
+	return fmt.Sprintf("%d", v)
+}
+
+func (r *RandomYear) Quote() string {
+	return r.String()
 }
 
-func NewRandomYearRange(name string, min, max int64, allowNull bool) *RandomIntRange {
-	return &RandomIntRange{name, min, max, allowNull}
+// NewRandomYear returns a new random year getter. Use DefaultTemporalRange("year") for the full
+// range of the year type.
+func NewRandomYear(name string, r TemporalRange, allowNull bool, rnd *rand.Rand) *RandomYear {
+	return &RandomYear{name, r, allowNull, rnd}
 }
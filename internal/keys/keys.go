@@ -0,0 +1,379 @@
+package keys
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Percona-Lab/mysql_random_data_load/internal/generators"
+	"github.com/Percona-Lab/mysql_random_data_load/internal/getters"
+	"github.com/Percona-Lab/mysql_random_data_load/tableparser"
+	log "github.com/sirupsen/logrus"
+)
+
+// Options holds the options used to build the getters of the unique keys
+type Options struct {
+	Seed  int64            // seed for the random values generators
+	Specs generators.Specs // user defined generators. Fields having one keep their getters
+	// TemporalRange returns the range of values for date, datetime, timestamp and year fields
+	TemporalRange func(dataType string) getters.TemporalRange
+	// TimeRange returns the range of values for time fields
+	TimeRange func() (time.Duration, time.Duration)
+	// Keep returns true for the fields whose getters must be kept, like profiled or inferred fields. If none of
+	// the fields of a unique key get distinct values from a domain, the duplicated values of the kept getters
+	// are skipped. It can be nil
+	Keep func(field tableparser.Field) bool
+}
+
+// Make replaces the getters of the fields in unique keys by getters generating distinct values, so rows
+// are not lost due to duplicated keys. fields are the fields having a getter in values, in the same order.
+// Fields having foreign keys, user defined generators or CHECK constraints keep their getters, like the fields
+// for which opts.Keep returns true. It returns an error if a unique key cannot have 'rows' distinct values.
+func Make(conn *sql.DB, table *tableparser.Table, fields []tableparser.Field, values []getters.Getter, rows int,
+	opts Options) error {
+	if len(fields) != len(values) {
+		return fmt.Errorf("the table %s has %d fields to insert but %d getters", table.Name, len(fields), len(values))
+	}
+	positions := make(map[string]int)
+	for i, field := range fields {
+		positions[field.ColumnName] = i
+	}
+	tableFields := make(map[string]tableparser.Field)
+	for _, field := range table.Fields {
+		tableFields[field.ColumnName] = field
+	}
+
+	assigned := make(map[string]bool)
+	uniqueKeys := [][]string{}
+	if pk, ok := table.Indexes["PRIMARY"]; ok && !isUniqueKey(pk.Fields, uniqueKeys, tableFields, positions) {
+		parts, err := makePrimaryKey(conn, table, pk, values, positions, rows, opts)
+		if err != nil {
+			return err
+		}
+		for i, part := range parts {
+			values[positions[pk.Fields[i]]] = part
+			assigned[pk.Fields[i]] = true
+		}
+		if parts != nil {
+			uniqueKeys = append(uniqueKeys, pk.Fields)
+		}
+	}
+	for _, index := range getUniqueIndexes(table) {
+		if isUniqueKey(index.Fields, uniqueKeys, tableFields, positions) {
+			continue
+		}
+		var names, kept []string
+		var domains []getters.Domain
+		for _, name := range index.Fields {
+			pos, inserted := positions[name]
+			if !inserted || assigned[name] || fields[pos].Constraint != nil {
+				continue
+			}
+			if _, checked := values[pos].(*getters.Checked); checked {
+				continue
+			}
+			field := fields[pos]
+			if _, ok := opts.Specs.Get(field.TableSchema, field.TableName, field.ColumnName); ok {
+				continue
+			}
+			if opts.Keep != nil && opts.Keep(field) {
+				kept = append(kept, name)
+				continue
+			}
+			if domain, ok := uniqueDomain(field, index.SubParts[name], opts); ok {
+				log.Debugf("Using distinct values for the %s field of the unique key %s instead of its %T getter",
+					name, index.Name, values[pos])
+				names = append(names, name)
+				domains = append(domains, domain)
+			}
+		}
+		if len(names) == 0 && len(kept) > 0 && index.Expression == "" {
+			// The kept getters are wrapped so they skip the duplicated values
+			log.Debugf("Skipping the duplicated values of (%s) for the unique key %s", strings.Join(kept, ", "),
+				index.Name)
+			keptValues := make([]getters.Getter, 0, len(kept))
+			prefixes := make([]int64, 0, len(kept))
+			for _, name := range kept {
+				keptValues = append(keptValues, values[positions[name]])
+				prefixes = append(prefixes, index.SubParts[name])
+			}
+			for i, part := range getters.NewDistinctKey(keptValues, prefixes).Parts() {
+				values[positions[kept[i]]] = part
+				assigned[kept[i]] = true
+			}
+			uniqueKeys = append(uniqueKeys, index.Fields)
+			continue
+		}
+		if len(names) == 0 || index.Expression != "" {
+			log.Warnf("Cannot generate distinct values for the unique key %s. Rows having duplicated keys will be discarded",
+				index.Name)
+			continue
+		}
+
+		first := fields[positions[names[0]]]
+		rnd := getters.NewColumnRand(opts.Seed, first.TableSchema, first.TableName, first.ColumnName)
+		key := getters.NewUniqueKey(domains, rnd)
+		if uint64(rows) > key.Size() {
+			if len(names) == len(index.Fields) {
+				return fmt.Errorf("the unique key %s allows only %d distinct values but %d rows were requested",
+					index.Name, key.Size(), rows)
+			}
+			log.Warnf("The unique key %s might not have %d distinct values. Rows having duplicated keys will be discarded",
+				index.Name, rows)
+		}
+		for i, part := range key.Parts() {
+			values[positions[names[i]]] = part
+			assigned[names[i]] = true
+		}
+		uniqueKeys = append(uniqueKeys, index.Fields)
+	}
+	return nil
+}
+
+// makePrimaryKey returns the getters for the primary key fields, in the same order as the key fields, using
+// a strategy chosen from the key definition:
+//   - integer keys: a sequence starting after the current max value
+//   - char/varchar(36) or longer keys: UUIDs
+//   - char/varchar keys having 26 to 35 chars: ULIDs
+//   - binary(16) keys: binary UUIDs
+//   - composite keys ending with an integer field, like (tenant_id, id): the prefix fields keep their
+//     getters and the last field is a counter per prefix, starting after the current max value
+//
+// It returns nil if none of the strategies can be used for the key. Keys having a field with a foreign key
+// (other than the prefix fields) or a user defined generator are not handled.
+func makePrimaryKey(conn *sql.DB, table *tableparser.Table, pk tableparser.Index, values []getters.Getter,
+	positions map[string]int, rows int, opts Options) ([]getters.Getter, error) {
+	fields := []tableparser.Field{}
+	for _, name := range pk.Fields {
+		for _, field := range table.Fields {
+			if field.ColumnName == name {
+				fields = append(fields, field)
+			}
+		}
+		if _, ok := positions[name]; !ok {
+			return nil, nil
+		}
+	}
+	if len(fields) != len(pk.Fields) || len(fields) == 0 || pk.Expression != "" {
+		return nil, nil
+	}
+	for _, field := range fields {
+		if _, ok := opts.Specs.Get(field.TableSchema, field.TableName, field.ColumnName); ok {
+			return nil, nil
+		}
+	}
+	if _, checked := values[positions[fields[len(fields)-1].ColumnName]].(*getters.Checked); checked {
+		return nil, nil
+	}
+
+	last := fields[len(fields)-1]
+	if last.Constraint != nil {
+		return nil, nil
+	}
+	// Kept fields other than integer fields, which use sequences, get distinct values as a unique key
+	if opts.Keep != nil && opts.Keep(last) && !getters.IsIntegerType(last.DataType) {
+		return nil, nil
+	}
+	rnd := getters.NewColumnRand(opts.Seed, last.TableSchema, last.TableName, last.ColumnName)
+
+	if len(fields) > 1 {
+		if !getters.IsIntegerType(last.DataType) {
+			return nil, nil
+		}
+		prefix := []getters.Getter{}
+		names := []string{}
+		for _, field := range fields[:len(fields)-1] {
+			prefix = append(prefix, values[positions[field.ColumnName]])
+			names = append(names, field.ColumnName)
+		}
+		counters, err := getPrefixCounters(conn, table, fields[:len(fields)-1], last.ColumnName)
+		if err != nil {
+			return nil, err
+		}
+		log.Debugf("Using a counter per (%s) prefix for the %s primary key field", strings.Join(names, ", "),
+			last.ColumnName)
+		return getters.NewCompositeKey(prefix, counters).Parts(), nil
+	}
+
+	switch {
+	case getters.IsIntegerType(last.DataType) && last.IsUnsigned():
+		max, err := MaxUintValue(conn, last)
+		if err != nil {
+			return nil, err
+		}
+		if typeMax := getters.UintMaxValue(last.DataType); uint64(rows) > typeMax || max > typeMax-uint64(rows) {
+			return nil, fmt.Errorf("the primary key field %s has a max value of %d and cannot have %d more values",
+				last.ColumnName, max, rows)
+		}
+		log.Debugf("Using a sequence starting at %d for the %s primary key field", max+1, last.ColumnName)
+		return []getters.Getter{getters.NewUintSequence(last.ColumnName, max+1)}, nil
+	case getters.IsIntegerType(last.DataType):
+		max, err := MaxValue(conn, last)
+		if err != nil {
+			return nil, err
+		}
+		if _, typeMax := getters.IntRange(last.DataType); max > typeMax-int64(rows) {
+			return nil, fmt.Errorf("the primary key field %s has a max value of %d and cannot have %d more values",
+				last.ColumnName, max, rows)
+		}
+		log.Debugf("Using a sequence starting at %d for the %s primary key field", max+1, last.ColumnName)
+		return []getters.Getter{getters.NewSequence(last.ColumnName, max+1)}, nil
+	case (last.DataType == "char" || last.DataType == "varchar") && last.CharacterMaximumLength.Int64 >= 36:
+		return []getters.Getter{getters.NewRandomUUID(last.ColumnName, 4, false, rnd)}, nil
+	case (last.DataType == "char" || last.DataType == "varchar") && last.CharacterMaximumLength.Int64 >= 26:
+		return []getters.Getter{getters.NewRandomULID(last.ColumnName, rnd)}, nil
+	case last.DataType == "binary" && last.CharacterOctetLength.Int64 == 16:
+		return []getters.Getter{getters.NewRandomUUID(last.ColumnName, 4, true, rnd)}, nil
+	}
+	return nil, nil
+}
+
+// MaxValue returns the max value of a signed integer field or 0 if the table is empty or there is
+// no connection, like when printing the queries without a server
+func MaxValue(conn *sql.DB, field tableparser.Field) (int64, error) {
+	var max int64
+	return max, queryMaxValue(conn, field, &max)
+}
+
+// MaxUintValue returns the max value of an unsigned integer field or 0 if the table is empty or there is
+// no connection. Values above the int64 range are scanned as uint64
+func MaxUintValue(conn *sql.DB, field tableparser.Field) (uint64, error) {
+	var max uint64
+	return max, queryMaxValue(conn, field, &max)
+}
+
+// queryMaxValue scans the max value of the field into dest
+func queryMaxValue(conn *sql.DB, field tableparser.Field, dest interface{}) error {
+	if conn == nil {
+		return nil
+	}
+	query := fmt.Sprintf("SELECT COALESCE(MAX(`%s`), 0) FROM `%s`.`%s`", field.ColumnName, field.TableSchema,
+		field.TableName)
+	if err := conn.QueryRow(query).Scan(dest); err != nil {
+		return fmt.Errorf("cannot get the max value for field %q: %s", field.ColumnName, err)
+	}
+	return nil
+}
+
+// getPrefixCounters returns the max value of the counter field for each prefix already in the table,
+// using getters.PrefixKey to build the map keys. The prefix values are converted with getters.PrefixValue
+// so their keys match the keys of the generated prefixes
+func getPrefixCounters(conn *sql.DB, table *tableparser.Table, prefix []tableparser.Field,
+	counter string) (map[string]int64, error) {
+	counters := make(map[string]int64)
+	if conn == nil {
+		return counters, nil
+	}
+	names := []string{}
+	for _, field := range prefix {
+		names = append(names, field.ColumnName)
+	}
+	query := fmt.Sprintf("SELECT `%s`, MAX(`%s`) FROM `%s`.`%s` GROUP BY `%s`", strings.Join(names, "`, `"),
+		counter, table.Schema, table.Name, strings.Join(names, "`, `"))
+	rows, err := conn.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get the primary key counters: %s, %s", query, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		values := make([]interface{}, len(prefix))
+		dest := make([]interface{}, 0, len(prefix)+1)
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		var max int64
+		if err := rows.Scan(append(dest, &max)...); err != nil {
+			return nil, fmt.Errorf("cannot get the primary key counters: %s", err)
+		}
+		for i, field := range prefix {
+			values[i] = getters.PrefixValue(field.DataType, values[i])
+		}
+		counters[getters.PrefixKey(values)] = max
+	}
+	return counters, rows.Err()
+}
+
+// getUniqueIndexes returns the unique indexes of the table: the primary key first and then the other
+// unique indexes having less fields first
+func getUniqueIndexes(table *tableparser.Table) []tableparser.Index {
+	indexes := []tableparser.Index{}
+	for _, index := range table.Indexes {
+		if index.Unique {
+			indexes = append(indexes, index)
+		}
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		if (indexes[i].Name == "PRIMARY") != (indexes[j].Name == "PRIMARY") {
+			return indexes[i].Name == "PRIMARY"
+		}
+		if len(indexes[i].Fields) != len(indexes[j].Fields) {
+			return len(indexes[i].Fields) < len(indexes[j].Fields)
+		}
+		return indexes[i].Name < indexes[j].Name
+	})
+	return indexes
+}
+
+// isUniqueKey returns true if the fields already have distinct values: if one of them is an auto increment
+// field filled by the server or they include all the fields of a key in uniqueKeys
+func isUniqueKey(names []string, uniqueKeys [][]string, fields map[string]tableparser.Field,
+	positions map[string]int) bool {
+	included := make(map[string]bool)
+	for _, name := range names {
+		included[name] = true
+		_, inserted := positions[name]
+		if !inserted && strings.Contains(strings.ToLower(fields[name].Extra), "auto_increment") {
+			return true
+		}
+	}
+	for _, key := range uniqueKeys {
+		all := true
+		for _, name := range key {
+			all = all && included[name]
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueDomain returns the domain used to generate distinct values for a field in a unique key. If the key
+// indexes a prefix of the field, subPart is the length of the prefix, so string values differ within it.
+func uniqueDomain(field tableparser.Field, subPart int64, opts Options) (getters.Domain, bool) {
+	length := field.CharacterMaximumLength.Int64
+	if field.DataType == "binary" || field.DataType == "varbinary" {
+		length = field.CharacterOctetLength.Int64
+	}
+	if subPart > 0 && subPart < length {
+		length = subPart
+	}
+	switch field.DataType {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint":
+		if field.IsUnsigned() {
+			return getters.NewUintDomain(0, getters.UintMaxValue(field.DataType)), true
+		}
+		return getters.NewIntDomain(getters.IntRange(field.DataType)), true
+	case "decimal":
+		return getters.NewDecimalDomain(field.NumericPrecision.Int64, field.NumericScale.Int64), true
+	case "char", "varchar", "binary", "varbinary":
+		return getters.NewStringDomain(length), true
+	case "enum":
+		return getters.NewEnumDomain(field.SetEnumVals), true
+	case "bit":
+		return getters.NewBitDomain(field.NumericPrecision.Int64), true
+	case "date", "datetime", "timestamp":
+		return getters.NewDateTimeDomain(field.DataType, opts.TemporalRange(field.DataType),
+			field.DatetimePrecision.Int64), true
+	case "year":
+		r := opts.TemporalRange(field.DataType)
+		return getters.NewIntDomain(int64(r.Min.Year()), int64(r.Max.Year())), true
+	case "time":
+		min, max := opts.TimeRange()
+		return getters.NewTimeDomain(min, max, field.DatetimePrecision.Int64), true
+	}
+	return nil, false
+}
@@ -0,0 +1,90 @@
+package keys
+
+import (
+	"database/sql"
+	"math/rand"
+	"testing"
+
+	"github.com/Percona-Lab/mysql_random_data_load/internal/getters"
+	"github.com/Percona-Lab/mysql_random_data_load/tableparser"
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestMake(t *testing.T) {
+	table := &tableparser.Table{
+		Schema: "test",
+		Name:   "t1",
+		Fields: []tableparser.Field{
+			{TableSchema: "test", TableName: "t1", ColumnName: "id", DataType: "int", ColumnType: "int unsigned"},
+			{TableSchema: "test", TableName: "t1", ColumnName: "code", DataType: "char",
+				CharacterMaximumLength: sql.NullInt64{Int64: 1, Valid: true}},
+			{TableSchema: "test", TableName: "t1", ColumnName: "name", DataType: "varchar",
+				CharacterMaximumLength: sql.NullInt64{Int64: 10, Valid: true}},
+		},
+		Indexes: map[string]tableparser.Index{
+			"PRIMARY": {Name: "PRIMARY", Unique: true, Fields: []string{"id"}},
+			"uk_code": {Name: "uk_code", Unique: true, Fields: []string{"code"}},
+		},
+	}
+	rnd := rand.New(rand.NewSource(1))
+	newValues := func() []getters.Getter {
+		return []getters.Getter{
+			getters.NewRandomInt("id", 10, false, rnd),
+			getters.NewRandomString("code", 1, false, rnd),
+			getters.NewRandomString("name", 10, false, rnd),
+		}
+	}
+	opts := Options{Seed: 1, TemporalRange: getters.DefaultTemporalRange, TimeRange: getters.DefaultTimeRange}
+
+	values := newValues()
+	tu.Ok(t, Make(nil, table, table.Fields, values, 36, opts))
+	codes := make(map[string]bool)
+	for i := 1; i <= 36; i++ {
+		tu.Equals(t, uint64(i), values[0].Value())
+		code := values[1].String()
+		tu.Assert(t, !codes[code], "Duplicated code %s", code)
+		codes[code] = true
+	}
+
+	// char(1) has 36 distinct values
+	tu.NotOk(t, Make(nil, table, table.Fields, newValues(), 37, opts))
+
+	// Unsigned keys use unsigned sequences and the max value of the type
+	table.Fields[0].DataType, table.Fields[0].ColumnType = "bigint", "bigint unsigned"
+	values = newValues()
+	tu.Ok(t, Make(nil, table, table.Fields, values, 10, opts))
+	tu.Equals(t, uint64(1), values[0].Value())
+	table.Fields[0].DataType, table.Fields[0].ColumnType = "tinyint", "tinyint unsigned"
+	delete(table.Indexes, "uk_code")
+	tu.Ok(t, Make(nil, table, table.Fields, newValues(), 255, opts))
+	tu.NotOk(t, Make(nil, table, table.Fields, newValues(), 256, opts))
+
+	// String domains are sized with the index prefix length
+	table.Indexes["uk_name"] = tableparser.Index{Name: "uk_name", Unique: true, Fields: []string{"name"},
+		SubParts: map[string]int64{"name": 1}}
+	tu.NotOk(t, Make(nil, table, table.Fields, newValues(), 37, opts))
+	values = newValues()
+	tu.Ok(t, Make(nil, table, table.Fields, values, 36, opts))
+	prefixes := make(map[string]bool)
+	for i := 0; i < 36; i++ {
+		prefix := values[2].String()[:1]
+		tu.Assert(t, !prefixes[prefix], "Duplicated prefix %s", prefix)
+		prefixes[prefix] = true
+	}
+
+	// Kept fields keep their getters, skipping the duplicated values
+	opts.Keep = func(field tableparser.Field) bool { return field.ColumnName == "name" }
+	values = newValues()
+	values[2] = getters.NewRandomSample("name", []interface{}{"alpha", "beta", "gamma"}, false, rnd)
+	tu.Ok(t, Make(nil, table, table.Fields, values, 10, opts))
+	names := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		name := values[2].Quote()
+		tu.Assert(t, !names[name], "Duplicated name %s", name)
+		names[name] = true
+	}
+	tu.Equals(t, map[string]bool{"'alpha'": true, "'beta'": true, "'gamma'": true}, names)
+
+	// The fields must match the getters
+	tu.NotOk(t, Make(nil, table, table.Fields[:2], newValues(), 10, opts))
+}
@@ -0,0 +1,123 @@
+package loaddata
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Percona-Lab/mysql_random_data_load/internal/getters"
+)
+
+// Format is the output format for the rows
+type Format string
+
+const (
+	// CSV format: fields are enclosed by double quotes and separated by commas
+	CSV Format = "csv"
+	// TSV format: fields are separated by tabs. This is the LOAD DATA INFILE default format
+	TSV Format = "tsv"
+
+	// Null is how NULLs are written. LOAD DATA INFILE reads \N as NULL
+	Null = `\N`
+)
+
+// Writer writes rows in a format that can be loaded using LOAD DATA INFILE
+type Writer struct {
+	w      *bufio.Writer
+	format Format
+}
+
+// NewWriter returns a new Writer writing rows in the specified format to w
+func NewWriter(w io.Writer, format Format) *Writer {
+	return &Writer{bufio.NewWriter(w), format}
+}
+
+// WriteRow writes a single row. Values are the getters String() values, so getters.NULL is
+// written as NULL
+func (w *Writer) WriteRow(values []string) error {
+	for i, value := range values {
+		if i > 0 {
+			if err := w.w.WriteByte(w.separator()); err != nil {
+				return err
+			}
+		}
+		if _, err := w.w.WriteString(w.field(value)); err != nil {
+			return err
+		}
+	}
+	return w.w.WriteByte('\n')
+}
+
+// Flush writes any buffered data to the underlying io.Writer
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+func (w *Writer) separator() byte {
+	if w.format == CSV {
+		return ','
+	}
+	return '\t'
+}
+
+func (w *Writer) field(value string) string {
+	if value == getters.NULL {
+		return Null
+	}
+	if w.format == CSV {
+		return `"` + Escape(value, '"') + `"`
+	}
+	return Escape(value, 0)
+}
+
+// Escape escapes the value using the LOAD DATA INFILE default escape character (\).
+// If enclosedBy is not 0, that character is also escaped.
+func Escape(value string, enclosedBy byte) string {
+	var b strings.Builder
+	b.Grow(len(value))
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch c {
+		case 0:
+			b.WriteString(`\0`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case 26:
+			b.WriteString(`\Z`)
+		default:
+			if enclosedBy != 0 && c == enclosedBy {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// Statement returns the LOAD DATA INFILE statement needed to load a file written
+// in the specified format into a table. fields must be already quoted with backticks.
+// The file is read using the binary character set so binary values are loaded byte by byte;
+// text values are already UTF-8.
+func Statement(format Format, filename, schema, table string, fields []string, local bool) string {
+	localKeyword := ""
+	if local {
+		localKeyword = "LOCAL "
+	}
+
+	fieldsClause := `FIELDS TERMINATED BY '\t' ESCAPED BY '\\'`
+	if format == CSV {
+		fieldsClause = `FIELDS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '"' ESCAPED BY '\\'`
+	}
+
+	return fmt.Sprintf("LOAD DATA %sINFILE %s IGNORE INTO TABLE `%s`.`%s` CHARACTER SET binary %s "+
+		"LINES TERMINATED BY '\\n' (%s);",
+		localKeyword, getters.QuoteString(filename), schema, table, fieldsClause,
+		strings.Join(fields, ","))
+}
@@ -0,0 +1,40 @@
+package loaddata
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Percona-Lab/mysql_random_data_load/internal/getters"
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestWriteRow(t *testing.T) {
+	row := []string{"1", getters.NULL, "a \"quoted\", string", "tab\there", "new\nline", `back\slash`, "zero\x00"}
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{CSV, `"1",\N,"a \"quoted\", string","tab\there","new\nline","back\\slash","zero\0"` + "\n"},
+		{TSV, `1	\N	a "quoted", string	tab\there	new\nline	back\\slash	zero\0` + "\n"},
+	}
+
+	for _, test := range tests {
+		buf := &bytes.Buffer{}
+		w := NewWriter(buf, test.format)
+		tu.Ok(t, w.WriteRow(row))
+		tu.Ok(t, w.Flush())
+		tu.Equals(t, test.want, buf.String())
+	}
+}
+
+func TestStatement(t *testing.T) {
+	want := "LOAD DATA INFILE '/tmp/film.csv' IGNORE INTO TABLE `sakila`.`film` CHARACTER SET binary " +
+		`FIELDS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '"' ESCAPED BY '\\' ` +
+		"LINES TERMINATED BY '\\n' (`title`,`description`);"
+	tu.Equals(t, want, Statement(CSV, "/tmp/film.csv", "sakila", "film", []string{"`title`", "`description`"}, false))
+
+	want = "LOAD DATA LOCAL INFILE 'it\\'s.tsv' IGNORE INTO TABLE `sakila`.`film` CHARACTER SET binary " +
+		`FIELDS TERMINATED BY '\t' ESCAPED BY '\\' ` +
+		"LINES TERMINATED BY '\\n' (`title`);"
+	tu.Equals(t, want, Statement(TSV, "it's.tsv", "sakila", "film", []string{"`title`"}, true))
+}
@@ -0,0 +1,173 @@
+package profile
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/Percona-Lab/mysql_random_data_load/internal/getters"
+	"github.com/Percona-Lab/mysql_random_data_load/tableparser"
+)
+
+const (
+	// maxPoolSize is the maximum number of distinct values of the histograms of a column. Columns having
+	// more distinct values get new random values for each row
+	maxPoolSize = 100000
+	// maxPoolTries is the number of values generated for each value of a pool looking for distinct values
+	maxPoolTries = 10
+)
+
+// NewGetter returns a getter generating values having the distribution in the profile of the field: the most
+// common values with their frequencies and the rest of the values following the values or lengths histograms.
+// The values of the histograms are chosen from a pool having the number of distinct values of the profile.
+func NewGetter(field tableparser.Field, col Column, rnd *rand.Rand) (getters.Getter, error) {
+	if valueClass(col.DataType) != valueClass(field.DataType) {
+		return nil, fmt.Errorf("the profile is for a %s field but the field is %s", col.DataType, field.DataType)
+	}
+	parts := []getters.Getter{}
+	weights := []float64{}
+	for _, v := range col.MostCommon {
+		parts = append(parts, getters.NewConstant(fieldValue(field, v.Value)))
+		weights = append(weights, v.Frequency)
+	}
+
+	buckets := []getters.Getter{}
+	bucketWeights := []float64{}
+	for _, b := range col.Histogram {
+		g, err := makeBucketGetter(field, b, rnd)
+		if err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, g)
+		bucketWeights = append(bucketWeights, b.Frequency)
+	}
+	for _, b := range col.Lengths {
+		// Profiles can be loaded into fields narrower than the profiled ones
+		if field.CharacterMaximumLength.Valid && b.Max > field.CharacterMaximumLength.Int64 {
+			b.Max = field.CharacterMaximumLength.Int64
+		}
+		buckets = append(buckets, getters.NewRandomStringRange(field.ColumnName, b.Min, b.Max, false, rnd))
+		bucketWeights = append(bucketWeights, b.Frequency)
+	}
+
+	total := 0.0
+	for _, w := range bucketWeights {
+		total += w
+	}
+	// The distinct values are split among the buckets rounding their cumulative frequencies, so the sizes
+	// of the pools add up to the number of distinct values
+	distinct := col.Distinct - int64(len(col.MostCommon))
+	cumulative, prev := 0.0, int64(0)
+	for i, g := range buckets {
+		if distinct > 0 && distinct <= maxPoolSize && total > 0 {
+			cumulative += bucketWeights[i]
+			end := int64(math.Round(float64(distinct) * cumulative / total))
+			size := end - prev
+			if size < 1 {
+				size = 1
+			}
+			prev = end
+			g = makePool(field, g, size, rnd)
+		}
+		parts = append(parts, g)
+		weights = append(weights, bucketWeights[i])
+	}
+
+	w := getters.NewWeighted(parts, weights, rnd)
+	if w == nil {
+		return nil, fmt.Errorf("the profile has no values")
+	}
+	if field.IsNullable && col.NullRatio > 0 {
+		return getters.NewNullRatio(w, col.NullRatio, rnd), nil
+	}
+	return w, nil
+}
+
+// makePool returns a getter choosing among up to size distinct values generated by g. Narrow buckets can
+// have less values than size.
+func makePool(field tableparser.Field, g getters.Getter, size int64, rnd *rand.Rand) getters.Getter {
+	seen := make(map[string]bool)
+	values := []interface{}{}
+	for i := int64(0); i < size*maxPoolTries && int64(len(values)) < size; i++ {
+		v := g.String()
+		if !seen[v] {
+			seen[v] = true
+			values = append(values, fieldValue(field, v))
+		}
+	}
+	return getters.NewRandomSample(field.ColumnName, values, false, rnd)
+}
+
+// makeBucketGetter returns a getter for values in the range of a histogram bucket
+func makeBucketGetter(field tableparser.Field, b Bucket, rnd *rand.Rand) (getters.Getter, error) {
+	switch valueClass(field.DataType) {
+	case "int":
+		if lower, err := strconv.ParseInt(b.Lower, 10, 64); err == nil {
+			upper, err := strconv.ParseInt(b.Upper, 10, 64)
+			if err == nil {
+				return getters.NewRandomIntRange(field.ColumnName, lower, upper, false, rnd), nil
+			}
+		}
+		lower, err := strconv.ParseUint(b.Lower, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bucket lower bound %q", b.Lower)
+		}
+		upper, err := strconv.ParseUint(b.Upper, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bucket upper bound %q", b.Upper)
+		}
+		return getters.NewRandomUintRange(field.ColumnName, lower, upper, false, rnd), nil
+	case "float":
+		lower, err := strconv.ParseFloat(b.Lower, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bucket lower bound %q", b.Lower)
+		}
+		upper, err := strconv.ParseFloat(b.Upper, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bucket upper bound %q", b.Upper)
+		}
+		scale := int64(-1)
+		if field.NumericScale.Valid {
+			scale = field.NumericScale.Int64
+		}
+		return getters.NewRandomFloatRange(field.ColumnName, lower, upper, scale, false, rnd), nil
+	case "temporal":
+		// Fractional seconds are not used for the bounds
+		lower, upper := strings.SplitN(b.Lower, ".", 2)[0], strings.SplitN(b.Upper, ".", 2)[0]
+		return getters.NewRandomDateInRange(field.ColumnName, field.DataType, field.DatetimePrecision.Int64,
+			lower, upper, false, rnd)
+	}
+	return nil, fmt.Errorf("histograms are not supported for %s fields", field.DataType)
+}
+
+// fieldValue converts a value in a profile to the type of the field. Integers are converted to numbers
+// and the rest of the values are kept as strings
+func fieldValue(field tableparser.Field, value string) interface{} {
+	if valueClass(field.DataType) != "int" {
+		return value
+	}
+	if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return v
+	}
+	if v, err := strconv.ParseUint(value, 10, 64); err == nil {
+		return v
+	}
+	return value
+}
+
+// valueClass groups the data types having compatible profiles
+func valueClass(dataType string) string {
+	switch {
+	case getters.IsIntegerType(dataType) || dataType == "year":
+		return "int"
+	case dataType == "decimal" || dataType == "float" || dataType == "double":
+		return "float"
+	case dataType == "date" || dataType == "datetime" || dataType == "timestamp":
+		return "temporal"
+	case getters.IsStringType(dataType):
+		return "string"
+	}
+	return dataType
+}
@@ -0,0 +1,106 @@
+package profile
+
+import (
+	"database/sql"
+	"math/rand"
+	"testing"
+
+	"github.com/Percona-Lab/mysql_random_data_load/tableparser"
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestNewGetter(t *testing.T) {
+	fields := map[string]tableparser.Field{
+		"rental_duration": {ColumnName: "rental_duration", DataType: "tinyint", ColumnType: "tinyint unsigned"},
+		"release_year":    {ColumnName: "release_year", DataType: "year", ColumnType: "year", IsNullable: true},
+		"last_update":     {ColumnName: "last_update", DataType: "timestamp", ColumnType: "timestamp"},
+		"title": {ColumnName: "title", DataType: "varchar", ColumnType: "varchar(255)",
+			CharacterMaximumLength: sql.NullInt64{Int64: 255, Valid: true}},
+	}
+	rnd := rand.New(rand.NewSource(1))
+
+	g, err := NewGetter(fields["rental_duration"], Column{
+		DataType:   "tinyint",
+		MostCommon: []Value{{Value: "3", Frequency: 0.5}},
+		Histogram:  []Bucket{{Lower: "4", Upper: "7", Frequency: 0.5}},
+	}, rnd)
+	tu.Ok(t, err)
+	counts := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		v := g.Quote()
+		tu.Assert(t, v >= "3" && v <= "7", "Invalid rental_duration %s", v)
+		counts[v]++
+	}
+	tu.Assert(t, counts["3"] > 4500 && counts["3"] < 5500, "Invalid frequency %d for 3", counts["3"])
+
+	// release_year is nullable
+	g, err = NewGetter(fields["release_year"], Column{
+		DataType:   "year",
+		NullRatio:  0.5,
+		MostCommon: []Value{{Value: "2006", Frequency: 1}},
+	}, rnd)
+	tu.Ok(t, err)
+	nulls := 0
+	for i := 0; i < 1000; i++ {
+		if g.Value() == nil {
+			nulls++
+		}
+	}
+	tu.Assert(t, nulls > 400 && nulls < 600, "Invalid number of NULLs %d", nulls)
+
+	g, err = NewGetter(fields["last_update"], Column{
+		DataType:  "datetime",
+		Histogram: []Bucket{{Lower: "2020-01-01 00:00:00.500", Upper: "2020-01-31 00:00:00", Frequency: 1}},
+	}, rnd)
+	tu.Ok(t, err)
+	for i := 0; i < 100; i++ {
+		v := g.String()
+		tu.Assert(t, v >= "2020-01-01" && v <= "2020-01-31", "Invalid last_update %s", v)
+	}
+
+	g, err = NewGetter(fields["title"], Column{
+		DataType: "varchar",
+		Lengths:  []LengthBucket{{Min: 20, Max: 25, Frequency: 1}},
+	}, rnd)
+	tu.Ok(t, err)
+	for i := 0; i < 100; i++ {
+		v := g.String()
+		tu.Assert(t, len(v) >= 20 && len(v) <= 25, "Invalid title %q", v)
+	}
+
+	// The histogram values have the number of distinct values in the profile
+	g, err = NewGetter(fields["rental_duration"], Column{
+		DataType:   "int",
+		Distinct:   6,
+		MostCommon: []Value{{Value: "3", Frequency: 0.5}},
+		Histogram:  []Bucket{{Lower: "0", Upper: "100", Frequency: 0.25}, {Lower: "101", Upper: "200", Frequency: 0.25}},
+	}, rnd)
+	tu.Ok(t, err)
+	counts = make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		counts[g.String()]++
+	}
+	tu.Equals(t, 6, len(counts))
+
+	// Lengths are limited to the field length
+	title := fields["title"]
+	title.CharacterMaximumLength.Int64 = 10
+	g, err = NewGetter(title, Column{
+		DataType: "varchar",
+		Distinct: 50,
+		Lengths:  []LengthBucket{{Min: 20, Max: 25, Frequency: 1}},
+	}, rnd)
+	tu.Ok(t, err)
+	counts = make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		v := g.String()
+		tu.Assert(t, len(v) <= 10, "Invalid title %q", v)
+		counts[v]++
+	}
+	tu.Assert(t, len(counts) <= 50, "Invalid number of distinct titles %d", len(counts))
+
+	_, err = NewGetter(fields["title"], Column{DataType: "int"}, rnd)
+	tu.NotOk(t, err)
+	_, err = NewGetter(fields["title"], Column{DataType: "varchar"}, rnd)
+	tu.NotOk(t, err)
+}
@@ -0,0 +1,511 @@
+// Package profile collects the distribution of the values of existing tables, so new data can be
+// generated having the same distributions.
+package profile
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Percona-Lab/mysql_random_data_load/tableparser"
+)
+
+const (
+	// maxMostCommon is the maximum number of most common values kept for a column, except for
+	// enum and set columns, which keep all their values
+	maxMostCommon = 20
+	// minCommonFrequency is the minimum frequency of a most common value
+	minCommonFrequency = 0.01
+	// maxBuckets is the maximum number of buckets in values and lengths histograms
+	maxBuckets = 16
+)
+
+// Profile maps fully qualified column names (schema.table.column) to the distribution of their values
+type Profile map[string]Column
+
+// Column holds the distribution of the values of a column. Frequencies are ratios (0 ~ 1) of the non NULL
+// values, so the frequencies of the most common values and of the histogram buckets add up to 1.
+type Column struct {
+	DataType string `json:"data_type"`
+	// Source is "sample" if the distribution was computed from a sample of the rows or "histogram" if
+	// it was read from the histogram of the column in information_schema.COLUMN_STATISTICS
+	Source    string  `json:"source"`
+	Rows      int64   `json:"rows"`
+	NullRatio float64 `json:"null_ratio"`
+	Distinct  int64   `json:"distinct"`
+	Min       string  `json:"min,omitempty"`
+	Max       string  `json:"max,omitempty"`
+	// MostCommon are the most common values and their frequencies
+	MostCommon []Value `json:"most_common,omitempty"`
+	// Histogram has the distribution of the rest of the values of numeric and temporal columns
+	Histogram []Bucket `json:"histogram,omitempty"`
+	// Lengths has the distribution of the lengths (in chars) of the rest of the values of string columns
+	Lengths []LengthBucket `json:"lengths,omitempty"`
+}
+
+// Value is a value and its frequency
+type Value struct {
+	Value     string  `json:"value"`
+	Frequency float64 `json:"frequency"`
+}
+
+// Bucket holds the frequency of the values between Lower and Upper, both included
+type Bucket struct {
+	Lower     string  `json:"lower"`
+	Upper     string  `json:"upper"`
+	Frequency float64 `json:"frequency"`
+}
+
+// LengthBucket holds the frequency of the values having between Min and Max chars
+type LengthBucket struct {
+	Min       int64   `json:"min"`
+	Max       int64   `json:"max"`
+	Frequency float64 `json:"frequency"`
+}
+
+// Load reads a profile file
+func Load(filename string) (Profile, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read profile file %q: %s", filename, err)
+	}
+	p := make(Profile)
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("cannot parse profile file %q: %s", filename, err)
+	}
+	for column := range p {
+		if strings.Count(column, ".") != 2 {
+			return nil, fmt.Errorf("invalid column name %q. Column names must be in the form schema.table.column", column)
+		}
+	}
+	return p, nil
+}
+
+// Write writes the profile as JSON
+func (p Profile) Write(w io.Writer) error {
+	buf, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(buf, '\n'))
+	return err
+}
+
+// Get returns the profile of a column. If there is no profile for the column in the schema, the profile
+// of the column in a table having the same name in another schema is returned, so a profile taken from
+// a database can be used to load a copy of it having a different name.
+func (p Profile) Get(schema, table, column string) (Column, bool) {
+	if c, ok := p[schema+"."+table+"."+column]; ok {
+		return c, true
+	}
+	suffix := "." + table + "." + column
+	var found []string
+	for name := range p {
+		if strings.HasSuffix(name, suffix) && strings.Count(name, ".") == 2 {
+			found = append(found, name)
+		}
+	}
+	if len(found) != 1 {
+		return Column{}, false
+	}
+	return p[found[0]], true
+}
+
+// Collect returns the profile of the columns of a table. It reads a random sample of sampleSize rows and,
+// for the columns having histograms (MySQL 8.0+), the histograms in information_schema.COLUMN_STATISTICS.
+// If sampleSize is 0, only the histograms are used. Columns of types not supported, like blob, json or
+// spatial columns, are not profiled.
+func Collect(db *sql.DB, table *tableparser.Table, sampleSize int64) (Profile, error) {
+	fields := []tableparser.Field{}
+	for _, f := range table.Fields {
+		if typeClass(f.DataType) != "" {
+			fields = append(fields, f)
+		}
+	}
+	p := make(Profile)
+	if len(fields) == 0 {
+		return p, nil
+	}
+
+	if sampleSize > 0 {
+		samples, nulls, err := sample(db, table, fields, sampleSize)
+		if err != nil {
+			return nil, err
+		}
+		for i, f := range fields {
+			if len(samples[i])+int(nulls[i]) > 0 {
+				p[key(table, f)] = Summarize(f.DataType, samples[i], nulls[i])
+			}
+		}
+	}
+
+	histograms, err := getHistograms(db, table)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fields {
+		data, ok := histograms[f.ColumnName]
+		if !ok {
+			continue
+		}
+		h, err := ParseHistogram(f, data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse the histogram of column %s: %s", f.ColumnName, err)
+		}
+		if c, ok := p[key(table, f)]; ok {
+			// Lengths of string values are not in the histograms
+			if typeClass(f.DataType) == "string" && len(h.Histogram) == 0 && len(h.MostCommon) == 0 {
+				h.MostCommon, h.Lengths = c.MostCommon, c.Lengths
+			}
+			h.Rows = c.Rows
+		}
+		p[key(table, f)] = h
+	}
+	return p, nil
+}
+
+func key(table *tableparser.Table, f tableparser.Field) string {
+	return table.Schema + "." + table.Name + "." + f.ColumnName
+}
+
+// typeClass returns how the values of a type are profiled: int, float, temporal, string or enum.
+// It returns an empty string for the types that are not profiled
+func typeClass(dataType string) string {
+	switch dataType {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint", "year":
+		return "int"
+	case "decimal", "float", "double":
+		return "float"
+	case "date", "datetime", "timestamp":
+		return "temporal"
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext":
+		return "string"
+	case "enum", "set":
+		return "enum"
+	}
+	return ""
+}
+
+// sample reads about sampleSize random rows of the table. It returns the non NULL values and the number
+// of NULLs of each field
+func sample(db *sql.DB, table *tableparser.Table, fields []tableparser.Field, sampleSize int64) ([][]string, []int64, error) {
+	var tableRows sql.NullInt64
+	query := "SELECT TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?"
+	if err := db.QueryRow(query, table.Schema, table.Name).Scan(&tableRows); err != nil {
+		return nil, nil, fmt.Errorf("cannot get the number of rows of %s: %s", table.Name, err)
+	}
+
+	columns := []string{}
+	for _, f := range fields {
+		columns = append(columns, fmt.Sprintf("CAST(%s AS CHAR)", tableparser.Backticks(f.ColumnName)))
+	}
+	query = fmt.Sprintf("SELECT %s FROM %s.%s", strings.Join(columns, ", "), tableparser.Backticks(table.Schema),
+		tableparser.Backticks(table.Name))
+	// Each row of big tables is read with the same probability, so the sample has about sampleSize rows.
+	// Cutting the rows read with LIMIT would keep the first rows of the table. Small tables are sorted
+	// randomly instead, since TABLE_ROWS is an estimate.
+	if tableRows.Int64 > sampleSize {
+		query += fmt.Sprintf(" WHERE RAND() < %.6f", float64(sampleSize)/float64(tableRows.Int64))
+	} else {
+		query += fmt.Sprintf(" ORDER BY RAND() LIMIT %d", sampleSize)
+	}
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read the sample of %s: %s", table.Name, err)
+	}
+	defer rows.Close()
+
+	samples := make([][]string, len(fields))
+	nulls := make([]int64, len(fields))
+	values := make([]sql.NullString, len(fields))
+	dest := make([]interface{}, len(fields))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, nil, fmt.Errorf("cannot read the sample of %s: %s", table.Name, err)
+		}
+		for i, v := range values {
+			if !v.Valid {
+				nulls[i]++
+				continue
+			}
+			samples[i] = append(samples[i], v.String)
+		}
+	}
+	return samples, nulls, rows.Err()
+}
+
+// Summarize returns the distribution of the values of a column, given its non NULL values and its
+// number of NULLs
+func Summarize(dataType string, values []string, nulls int64) Column {
+	n := float64(len(values))
+	c := Column{DataType: dataType, Source: "sample", Rows: int64(len(values)) + nulls}
+	if c.Rows > 0 {
+		c.NullRatio = float64(nulls) / float64(c.Rows)
+	}
+	if len(values) == 0 {
+		return c
+	}
+
+	counts := make(map[string]int)
+	for _, v := range values {
+		counts[v]++
+	}
+	c.Distinct = int64(len(counts))
+
+	distinct := make([]string, 0, len(counts))
+	for v := range counts {
+		distinct = append(distinct, v)
+	}
+	sort.Slice(distinct, func(i, j int) bool {
+		if counts[distinct[i]] != counts[distinct[j]] {
+			return counts[distinct[i]] > counts[distinct[j]]
+		}
+		return distinct[i] < distinct[j]
+	})
+
+	class := typeClass(dataType)
+	common := make(map[string]bool)
+	for _, v := range distinct {
+		freq := float64(counts[v]) / n
+		if class != "enum" && len(counts) > maxMostCommon &&
+			(len(common) == maxMostCommon || counts[v] < 2 || freq < minCommonFrequency) {
+			break
+		}
+		c.MostCommon = append(c.MostCommon, Value{Value: v, Frequency: freq})
+		common[v] = true
+	}
+
+	rest := []string{}
+	for _, v := range values {
+		if !common[v] {
+			rest = append(rest, v)
+		}
+	}
+
+	switch class {
+	case "int", "float", "temporal":
+		less := func(a, b string) bool { return a < b }
+		if class != "temporal" {
+			less = func(a, b string) bool {
+				fa, _ := strconv.ParseFloat(a, 64)
+				fb, _ := strconv.ParseFloat(b, 64)
+				return fa < fb
+			}
+		}
+		sort.Slice(distinct, func(i, j int) bool { return less(distinct[i], distinct[j]) })
+		c.Min, c.Max = distinct[0], distinct[len(distinct)-1]
+		sort.Slice(rest, func(i, j int) bool { return less(rest[i], rest[j]) })
+		for _, b := range equiHeight(len(rest)) {
+			c.Histogram = append(c.Histogram, Bucket{Lower: rest[b[0]], Upper: rest[b[1]],
+				Frequency: float64(b[1]-b[0]+1) / n})
+		}
+	case "string":
+		lengths := make([]int64, len(rest))
+		for i, v := range rest {
+			lengths[i] = int64(utf8.RuneCountInString(v))
+		}
+		sort.Slice(lengths, func(i, j int) bool { return lengths[i] < lengths[j] })
+		for _, b := range equiHeight(len(lengths)) {
+			freq := float64(b[1]-b[0]+1) / n
+			// Buckets having the same bounds are merged
+			if last := len(c.Lengths) - 1; last >= 0 && c.Lengths[last].Min == lengths[b[0]] &&
+				c.Lengths[last].Max == lengths[b[1]] {
+				c.Lengths[last].Frequency += freq
+				continue
+			}
+			c.Lengths = append(c.Lengths, LengthBucket{Min: lengths[b[0]], Max: lengths[b[1]], Frequency: freq})
+		}
+	}
+	return c
+}
+
+// equiHeight splits n sorted values into up to maxBuckets buckets having the same number of values.
+// It returns the first and last index of each bucket
+func equiHeight(n int) [][2]int {
+	buckets := maxBuckets
+	if n < buckets {
+		buckets = n
+	}
+	ranges := [][2]int{}
+	for b := 0; b < buckets; b++ {
+		ranges = append(ranges, [2]int{b * n / buckets, (b+1)*n/buckets - 1})
+	}
+	return ranges
+}
+
+// getHistograms returns the histograms of the columns of the table. MySQL versions before 8.0 don't
+// have histograms, so an empty map is returned for them
+func getHistograms(db *sql.DB, table *tableparser.Table) (map[string][]byte, error) {
+	histograms := make(map[string][]byte)
+	var count int
+	query := "SELECT COUNT(*) FROM information_schema.TABLES " +
+		"WHERE TABLE_SCHEMA = 'information_schema' AND TABLE_NAME = 'COLUMN_STATISTICS'"
+	if err := db.QueryRow(query).Scan(&count); err != nil || count == 0 {
+		return histograms, nil
+	}
+
+	query = "SELECT COLUMN_NAME, HISTOGRAM FROM information_schema.COLUMN_STATISTICS " +
+		"WHERE SCHEMA_NAME = ? AND TABLE_NAME = ?"
+	rows, err := db.Query(query, table.Schema, table.Name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the histograms of %s: %s", table.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var column string
+		var histogram []byte
+		if err := rows.Scan(&column, &histogram); err != nil {
+			return nil, fmt.Errorf("cannot read the histograms of %s: %s", table.Name, err)
+		}
+		histograms[column] = histogram
+	}
+	return histograms, rows.Err()
+}
+
+// histogram is the JSON document describing a histogram in information_schema.COLUMN_STATISTICS
+type histogram struct {
+	Buckets       [][]interface{} `json:"buckets"`
+	NullValues    float64         `json:"null-values"`
+	HistogramType string          `json:"histogram-type"`
+	SamplingRate  float64         `json:"sampling-rate"`
+}
+
+// ParseHistogram returns the distribution of the values of a column given its histogram, as stored in
+// information_schema.COLUMN_STATISTICS. Singleton histograms are read as most common values and
+// equi-height histograms as values histograms. Equi-height histograms of string columns only give the
+// NULLs ratio and the number of distinct values since strings in them can be truncated.
+func ParseHistogram(field tableparser.Field, data []byte) (Column, error) {
+	var h histogram
+	d := json.NewDecoder(strings.NewReader(string(data)))
+	d.UseNumber()
+	if err := d.Decode(&h); err != nil {
+		return Column{}, err
+	}
+
+	c := Column{DataType: field.DataType, Source: "histogram", NullRatio: h.NullValues}
+	nonNull := 1 - h.NullValues
+	if nonNull <= 0 {
+		return c, nil
+	}
+	class := typeClass(field.DataType)
+
+	prev := 0.0
+	for _, b := range h.Buckets {
+		switch {
+		case h.HistogramType == "singleton" && len(b) == 2:
+			v, err := histogramValue(field, b[0])
+			if err != nil {
+				return Column{}, err
+			}
+			cum, err := toFloat(b[1])
+			if err != nil {
+				return Column{}, err
+			}
+			c.MostCommon = append(c.MostCommon, Value{Value: v, Frequency: (cum - prev) / nonNull})
+			c.Distinct++
+			prev = cum
+		case h.HistogramType == "equi-height" && len(b) == 4:
+			lower, err := histogramValue(field, b[0])
+			if err != nil {
+				return Column{}, err
+			}
+			upper, err := histogramValue(field, b[1])
+			if err != nil {
+				return Column{}, err
+			}
+			cum, err := toFloat(b[2])
+			if err != nil {
+				return Column{}, err
+			}
+			distinct, err := toFloat(b[3])
+			if err != nil {
+				return Column{}, err
+			}
+			c.Distinct += int64(distinct)
+			if class != "string" {
+				c.Histogram = append(c.Histogram, Bucket{Lower: lower, Upper: upper, Frequency: (cum - prev) / nonNull})
+			}
+			prev = cum
+		default:
+			return Column{}, fmt.Errorf("invalid %s histogram bucket %v", h.HistogramType, b)
+		}
+	}
+
+	if class == "int" || class == "float" || class == "temporal" {
+		if len(c.Histogram) > 0 {
+			c.Min, c.Max = c.Histogram[0].Lower, c.Histogram[len(c.Histogram)-1].Upper
+		}
+		if len(c.MostCommon) > 0 {
+			c.Min, c.Max = c.MostCommon[0].Value, c.MostCommon[len(c.MostCommon)-1].Value
+		}
+	}
+	return c, nil
+}
+
+// histogramValue returns a value in a histogram bucket as a string. Strings are encoded as
+// base64:type<N>:<base64 value>, enum values are their indexes and set values are bitmasks.
+func histogramValue(field tableparser.Field, v interface{}) (string, error) {
+	switch val := v.(type) {
+	case json.Number:
+		s := val.String()
+		if field.DataType != "enum" && field.DataType != "set" {
+			return s, nil
+		}
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return "", err
+		}
+		if field.DataType == "enum" {
+			if n < 1 || n > uint64(len(field.SetEnumVals)) {
+				return "", nil
+			}
+			return field.SetEnumVals[n-1], nil
+		}
+		members := []string{}
+		for i, m := range field.SetEnumVals {
+			if n&(1<<uint(i)) != 0 {
+				members = append(members, m)
+			}
+		}
+		return strings.Join(members, ","), nil
+	case string:
+		if strings.HasPrefix(val, "base64:") {
+			parts := strings.SplitN(val, ":", 3)
+			if len(parts) != 3 {
+				return "", fmt.Errorf("invalid histogram value %q", val)
+			}
+			b, err := base64.StdEncoding.DecodeString(parts[2])
+			return string(b), err
+		}
+		// Fractional seconds are removed from datetime values, like 2020-01-01 00:00:00.000000
+		if typeClass(field.DataType) == "temporal" && strings.HasSuffix(val, ".000000") {
+			val = strings.TrimSuffix(val, ".000000")
+		}
+		return val, nil
+	case bool:
+		if val {
+			return "1", nil
+		}
+		return "0", nil
+	}
+	return "", fmt.Errorf("invalid histogram value %v", v)
+}
+
+func toFloat(v interface{}) (float64, error) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("invalid number %v", v)
+	}
+	return n.Float64()
+}
@@ -0,0 +1,100 @@
+package profile
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/Percona-Lab/mysql_random_data_load/tableparser"
+	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+)
+
+func TestSummarize(t *testing.T) {
+	values := []string{}
+	for i := 0; i < 500; i++ {
+		values = append(values, "7")
+	}
+	for i := 0; i < 500; i++ {
+		values = append(values, fmt.Sprintf("%d", 100+i))
+	}
+	c := Summarize("int", values, 250)
+	tu.Equals(t, int64(1250), c.Rows)
+	tu.Equals(t, 0.2, c.NullRatio)
+	tu.Equals(t, int64(501), c.Distinct)
+	tu.Equals(t, "7", c.Min)
+	tu.Equals(t, "599", c.Max)
+	tu.Equals(t, []Value{{Value: "7", Frequency: 0.5}}, c.MostCommon)
+	tu.Equals(t, maxBuckets, len(c.Histogram))
+	tu.Equals(t, "100", c.Histogram[0].Lower)
+	tu.Equals(t, "599", c.Histogram[maxBuckets-1].Upper)
+	total := c.MostCommon[0].Frequency
+	for _, b := range c.Histogram {
+		total += b.Frequency
+	}
+	tu.Assert(t, total > 0.999 && total < 1.001, "Frequencies add up to %v", total)
+
+	c = Summarize("varchar", []string{"a", "bb", "ccc", "dddd"}, 0)
+	tu.Equals(t, 4, len(c.MostCommon))
+	tu.Equals(t, 0, len(c.Lengths))
+
+	values = []string{}
+	for i := 0; i < 1000; i++ {
+		values = append(values, fmt.Sprintf("%d", i))
+	}
+	c = Summarize("varchar", values, 0)
+	tu.Equals(t, 0, len(c.MostCommon))
+	tu.Equals(t, int64(1), c.Lengths[0].Min)
+	tu.Equals(t, LengthBucket{Min: 3, Max: 3, Frequency: 0.875}, c.Lengths[len(c.Lengths)-1])
+}
+
+func TestParseHistogram(t *testing.T) {
+	field := tableparser.Field{ColumnName: "rating", DataType: "enum", SetEnumVals: []string{"G", "PG", "R"}}
+	c, err := ParseHistogram(field, []byte(`{"buckets": [[1, 0.25], [3, 0.75]], "data-type": "enum",
+		"null-values": 0.25, "histogram-type": "singleton", "sampling-rate": 1.0}`))
+	tu.Ok(t, err)
+	tu.Equals(t, Column{DataType: "enum", Source: "histogram", NullRatio: 0.25, Distinct: 2,
+		MostCommon: []Value{{"G", 1.0 / 3}, {"R", 2.0 / 3}}}, c)
+
+	field = tableparser.Field{ColumnName: "created", DataType: "datetime"}
+	c, err = ParseHistogram(field, []byte(`{"buckets": [
+		["2020-01-01 00:00:00.000000", "2020-06-30 00:00:00.000000", 0.5, 100],
+		["2020-07-01 00:00:00.000000", "2020-12-31 00:00:00.000000", 1.0, 80]],
+		"null-values": 0.0, "histogram-type": "equi-height"}`))
+	tu.Ok(t, err)
+	tu.Equals(t, int64(180), c.Distinct)
+	tu.Equals(t, "2020-01-01 00:00:00", c.Min)
+	tu.Equals(t, "2020-12-31 00:00:00", c.Max)
+	tu.Equals(t, Bucket{Lower: "2020-07-01 00:00:00", Upper: "2020-12-31 00:00:00", Frequency: 0.5}, c.Histogram[1])
+
+	field = tableparser.Field{ColumnName: "name", DataType: "varchar"}
+	c, err = ParseHistogram(field, []byte(`{"buckets": [["base64:type254:YWJj", 0.4], ["base64:type254:eHl6", 1.0]],
+		"null-values": 0.0, "histogram-type": "singleton"}`))
+	tu.Ok(t, err)
+	tu.Equals(t, []Value{{"abc", 0.4}, {"xyz", 0.6}}, c.MostCommon)
+
+	_, err = ParseHistogram(field, []byte(`{"buckets": [[1, 2, 3]], "histogram-type": "singleton"}`))
+	tu.NotOk(t, err)
+}
+
+func TestProfileGet(t *testing.T) {
+	p := Profile{
+		"shop.orders.status":    {DataType: "varchar"},
+		"shop.customers.status": {DataType: "enum"},
+	}
+	c, ok := p.Get("shop", "orders", "status")
+	tu.Assert(t, ok, "Missing profile")
+	tu.Equals(t, "varchar", c.DataType)
+
+	// Profiles of other schemas are used if there is only one for the column
+	c, ok = p.Get("shop_copy", "customers", "status")
+	tu.Assert(t, ok, "Missing profile from another schema")
+	tu.Equals(t, "enum", c.DataType)
+
+	p["old_shop.customers.status"] = Column{DataType: "char"}
+	_, ok = p.Get("shop_copy", "customers", "status")
+	tu.Assert(t, !ok, "Ambiguous profiles should not be used")
+
+	var buf bytes.Buffer
+	tu.Ok(t, p.Write(&buf))
+	tu.Assert(t, bytes.Contains(buf.Bytes(), []byte(`"shop.orders.status"`)), "Invalid profile file")
+}
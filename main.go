@@ -2,16 +2,27 @@ package main
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
-	"net/url"
+	"io"
+	"io/ioutil"
+	"math/rand"
 	"os"
 	"os/user"
+	"path/filepath"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/Percona-Lab/mysql_random_data_load/internal/checks"
+	"github.com/Percona-Lab/mysql_random_data_load/internal/generators"
 	"github.com/Percona-Lab/mysql_random_data_load/internal/getters"
+	"github.com/Percona-Lab/mysql_random_data_load/internal/keys"
+	"github.com/Percona-Lab/mysql_random_data_load/internal/loaddata"
+	"github.com/Percona-Lab/mysql_random_data_load/internal/profile"
 	"github.com/Percona-Lab/mysql_random_data_load/tableparser"
 	"github.com/go-ini/ini"
 	"github.com/go-sql-driver/mysql"
@@ -25,25 +36,53 @@ import (
 type cliOptions struct {
 	app *kingpin.Application
 
+	// Command
+	Command string
 	// Arguments
 	Schema    *string
 	TableName *string
 	Rows      *int
+	Tables    *[]string
+	// Command flags
+	TableRows        *map[string]string
+	DefinitionOutput *string
+	ProfileOutput    *string
+	SampleSize       *int64
 	// Flags
-	BulkSize   *int
-	ConfigFile *string
-	Debug      *bool
-	Factor     *float64
-	Host       *string
-	MaxRetries *int
-	MaxThreads *int
-	NoProgress *bool
-	Pass       *string
-	Port       *int
-	Print      *bool
-	Samples    *int64
-	User       *string
-	Version    *bool
+	BoundingBox     *string
+	BulkSize        *int
+	ConfigFile      *string
+	DateRange       *string
+	DatetimeRange   *string
+	DDLFile         *string
+	Debug           *bool
+	Factor          *float64
+	GeneratorsFile  *string
+	Heuristics      *bool
+	ProfileFile     *string
+	Host            *string
+	MaxRetries      *int
+	MaxThreads      *int
+	LoadData        *bool
+	MaxBlobSize     *int64
+	LoadDataStmt    *bool
+	NoProgress      *bool
+	OutputDir       *string
+	OutputFormat    *string
+	Pass            *string
+	Port            *int
+	Print           *bool
+	RecentRatio     *float64
+	ReferenceTime   *string
+	Samples         *int64
+	Seed            *int64
+	SkipDefaults    *bool
+	TableDefinition *string
+	TimeRange       *string
+	TimestampRange  *string
+	User            *string
+	Version         *bool
+	YearRange       *string
 }
 
 type mysqlOptions struct {
@@ -57,19 +96,6 @@ type mysqlOptions struct {
 var (
 	opts *cliOptions
 
-	validFunctions = []string{"int", "string", "date", "date_in_range"}
-	maxValues      = map[string]int64{
-		"tinyint":   0XF,
-		"smallint":  0xFF,
-		"mediumint": 0x7FFFF,
-		"int":       0x7FFFFFFF,
-		"integer":   0x7FFFFFFF,
-		"float":     0x7FFFFFFF,
-		"decimal":   0x7FFFFFFF,
-		"double":    0x7FFFFFFF,
-		"bigint":    0x7FFFFFFFFFFFFFFF,
-	}
-
 	Version   = "0.0.0."
 	Commit    = "<sha1>"
 	Branch    = "branch-name"
@@ -77,11 +103,62 @@ var (
 	GoVersion = "1.9.2"
 )
 
-type getter interface {
-	Value() interface{}
-	Quote() string
-	String() string
+// valueFuncsOptions holds the options used to build the values generators for a table
+type valueFuncsOptions struct {
+	samples     int64               // maximum number of samples for foreign keys fields
+	specs       generators.Specs    // user defined generators
+	seed        int64               // seed for the random values generators
+	maxBlobSize int64               // maximum size for blob fields
+	bbox        getters.BoundingBox // area for the coordinates of spatial fields
+	// leave the fields having a default value out of the INSERT statements
+	skipDefaults bool
+	// infer the generators of the fields without user defined generators from their names and comments
+	heuristics bool
+	// distributions of the values of the fields, read from the profile file
+	profile profile.Profile
+	// ranges for date, datetime, timestamp and year fields. Missing types use the legal range of the type
+	temporalRanges map[string]getters.TemporalRange
+	// range for time fields. The zero value means the legal range of the time type
+	timeRange [2]time.Duration
+	// tables being loaded, to report their foreign keys cycles
+	tables []*tableparser.Table
+}
+
+// temporalRange returns the range of values for a date, datetime, timestamp or year field
+func (o valueFuncsOptions) temporalRange(dataType string) getters.TemporalRange {
+	if r, ok := o.temporalRanges[dataType]; ok {
+		return r
+	}
+	return getters.DefaultTemporalRange(dataType)
+}
+
+// timeMinMax returns the range of values for time fields
+func (o valueFuncsOptions) timeMinMax() (time.Duration, time.Duration) {
+	if o.timeRange == [2]time.Duration{} {
+		return getters.DefaultTimeRange()
+	}
+	return o.timeRange[0], o.timeRange[1]
 }
+
+// checksOptions returns the options used to build the getters satisfying the CHECK constraints
+func (o valueFuncsOptions) checksOptions() checks.Options {
+	return checks.Options{Seed: o.seed, Specs: o.specs, TemporalRange: o.temporalRange}
+}
+
+// keysOptions returns the options used to build the getters of the unique keys
+func (o valueFuncsOptions) keysOptions() keys.Options {
+	return keys.Options{Seed: o.seed, Specs: o.specs, TemporalRange: o.temporalRange, TimeRange: o.timeMinMax,
+		Keep: func(field tableparser.Field) bool {
+			// Profiled and inferred values are kept, skipping the duplicates
+			if _, ok := fieldProfile(field, o); ok {
+				return true
+			}
+			_, ok := fieldSpec(field, o)
+			return ok
+		}}
+}
+
+type getter = getters.Getter
 type insertValues []getter
 type insertFunction func(*sql.DB, string, chan int, chan bool, *sync.WaitGroup)
 
@@ -93,7 +170,8 @@ const (
 
 func main() {
 
-	opts, err := processCliParams()
+	var err error
+	opts, err = processCliParams()
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -107,6 +185,185 @@ func main() {
 		return
 	}
 
+	if *opts.DDLFile != "" && *opts.TableDefinition != "" {
+		log.Printf("--ddl-file and --table-definition cannot be used together")
+		os.Exit(1)
+	}
+
+	// A connection is not needed to describe or print the rows of tables defined in a file
+	var db *sql.DB
+	offline := *opts.DDLFile != "" || *opts.TableDefinition != ""
+	loadCommand := opts.Command == "table" || opts.Command == "schema"
+	if !offline || opts.Command == "profile" || loadCommand && *opts.OutputFormat == "insert" && !*opts.Print {
+		if db, err = connect(); err != nil {
+			log.Print(err)
+			os.Exit(1)
+		}
+	}
+
+	specs := generators.Specs{}
+	if *opts.GeneratorsFile != "" {
+		if specs, err = generators.Load(*opts.GeneratorsFile); err != nil {
+			log.Printf("cannot load the generators file: %s", err)
+			closeDB(db)
+			os.Exit(1)
+		}
+	}
+
+	var prof profile.Profile
+	if *opts.ProfileFile != "" {
+		if prof, err = profile.Load(*opts.ProfileFile); err != nil {
+			log.Print(err)
+			closeDB(db)
+			os.Exit(1)
+		}
+	}
+
+	log.SetFormatter(&log.TextFormatter{FullTimestamp: true})
+	if *opts.Debug {
+		log.SetLevel(log.DebugLevel)
+		*opts.NoProgress = true
+	}
+
+	if *opts.Seed == 0 {
+		*opts.Seed = time.Now().UnixNano()
+	}
+	log.Infof("Using seed %d", *opts.Seed)
+
+	if *opts.ReferenceTime != "" {
+		t, err := time.ParseInLocation("2006-01-02 15:04:05", *opts.ReferenceTime, time.UTC)
+		if err != nil {
+			log.Printf("invalid reference time %q: %s", *opts.ReferenceTime, err)
+			closeDB(db)
+			os.Exit(1)
+		}
+		getters.SetReferenceTime(t)
+	}
+
+	bbox, err := getters.ParseBoundingBox(*opts.BoundingBox)
+	if err != nil {
+		log.Print(err)
+		closeDB(db)
+		os.Exit(1)
+	}
+
+	temporalRanges, timeRange, err := getTemporalRanges()
+	if err != nil {
+		log.Print(err)
+		closeDB(db)
+		os.Exit(1)
+	}
+
+	tables, rows, err := getTables(db)
+	if err != nil {
+		log.Printf("cannot get tables: %s", err)
+		closeDB(db)
+		os.Exit(1)
+	}
+
+	if opts.Command == "generators" {
+		valueOpts := valueFuncsOptions{specs: specs, heuristics: *opts.Heuristics}
+		err := printGenerators(tables, valueOpts)
+		closeDB(db)
+		if err != nil {
+			log.Printf("cannot print the generators: %s", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if opts.Command == "profile" {
+		err := profileTables(db, tables, *opts.SampleSize, *opts.ProfileOutput)
+		closeDB(db)
+		if err != nil {
+			log.Printf("cannot profile the tables: %s", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if opts.Command == "describe" {
+		err := describeTables(tables, *opts.DefinitionOutput)
+		closeDB(db)
+		if err != nil {
+			log.Printf("cannot describe the tables: %s", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *opts.OutputFormat != "insert" && *opts.OutputDir == "" && len(tables) > 1 {
+		log.Printf("--output-dir is required to write %s output for more than one table", *opts.OutputFormat)
+		closeDB(db)
+		os.Exit(1)
+	}
+
+	if opts.Command == "table" && *opts.Rows < 1 {
+		closeDB(db)
+		log.Warnf("Number of rows < 1. There is nothing to do. Exiting")
+		os.Exit(1)
+	}
+
+	if opts.MaxThreads == nil {
+		*opts.MaxThreads = runtime.NumCPU() * 10
+	}
+
+	if *opts.MaxThreads < 1 {
+		*opts.MaxThreads = 1
+	}
+
+	if *opts.Print {
+		*opts.MaxThreads = 1
+		*opts.NoProgress = true
+	}
+	if *opts.OutputFormat != "insert" && *opts.OutputDir == "" {
+		*opts.NoProgress = true
+	}
+
+	if !*opts.Print {
+		log.Info("Starting")
+	}
+
+	if !*opts.NoProgress {
+		uiprogress.Start()
+	}
+
+	semaphores := makeSemaphores(*opts.MaxThreads)
+	valueOpts := valueFuncsOptions{
+		samples:     *opts.Samples,
+		specs:       specs,
+		seed:        *opts.Seed,
+		maxBlobSize: *opts.MaxBlobSize,
+		bbox:        bbox,
+
+		temporalRanges: temporalRanges,
+		timeRange:      timeRange,
+		skipDefaults:   *opts.SkipDefaults,
+		heuristics:     *opts.Heuristics,
+		profile:        prof,
+		tables:         tables,
+	}
+	for _, table := range tables {
+		if rows[table.Name] < 1 {
+			log.Warnf("Number of rows for table %s < 1. Skipping", table.Name)
+			continue
+		}
+		totalOkCount, err := loadTable(db, table, rows[table.Name], semaphores, valueOpts)
+		if err != nil {
+			log.Errorf("cannot load table %s: %s", table.Name, err)
+			continue
+		}
+		if !*opts.Print && *opts.OutputFormat == "insert" {
+			log.Printf("%d rows inserted into %s", totalOkCount, table.Name)
+		}
+	}
+
+	time.Sleep(500 * time.Millisecond) // Let the progress bar to update
+	closeDB(db)
+}
+
+// connect opens the connection to the MySQL server
+func connect() (*sql.DB, error) {
 	address := *opts.Host
 	net := "unix"
 	if address != "localhost" {
@@ -124,66 +381,263 @@ func main() {
 		DBName:               "",
 		ParseTime:            true,
 		AllowNativePasswords: true,
+		Collation:            "utf8mb4_general_ci",
 	}
 
 	db, err := sql.Open("mysql", dsn.FormatDSN())
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	db.SetMaxOpenConns(100)
 
 	// SET TimeZone to UTC to avoid errors due to random dates & daylight saving valid values
 	if _, err = db.Exec(`SET @@session.time_zone = "+00:00"`); err != nil {
-		log.Printf("Cannot set time zone to UTC: %s\n", err)
 		db.Close()
-		os.Exit(1)
+		return nil, fmt.Errorf("Cannot set time zone to UTC: %s", err)
 	}
 
-	table, err := tableparser.NewTable(db, *opts.Schema, *opts.TableName)
-	if err != nil {
-		log.Printf("cannot get table %s struct: %s", *opts.TableName, err)
+	// Values are quoted according to the sql_mode (NO_BACKSLASH_ESCAPES)
+	var sqlMode string
+	if err = db.QueryRow("SELECT @@SESSION.sql_mode").Scan(&sqlMode); err != nil {
 		db.Close()
-		os.Exit(1)
+		return nil, fmt.Errorf("Cannot get the sql_mode: %s", err)
 	}
+	getters.SetSQLMode(sqlMode)
 
-	log.SetFormatter(&log.TextFormatter{FullTimestamp: true})
-	if *opts.Debug {
-		log.SetLevel(log.DebugLevel)
-		*opts.NoProgress = true
+	return db, nil
+}
+
+// closeDB closes the connection, if there is one
+func closeDB(db *sql.DB) {
+	if db != nil {
+		db.Close() // golint:noerror
+	}
+}
+
+// getTemporalRanges returns the ranges for date, datetime, timestamp, year and time fields
+// set in the command line
+func getTemporalRanges() (map[string]getters.TemporalRange, [2]time.Duration, error) {
+	if *opts.RecentRatio < 0 || *opts.RecentRatio > 1 {
+		return nil, [2]time.Duration{}, fmt.Errorf("invalid recent ratio %v. It must be between 0 and 1", *opts.RecentRatio)
+	}
+	ranges := make(map[string]getters.TemporalRange)
+	flags := map[string]string{
+		"date":      *opts.DateRange,
+		"datetime":  *opts.DatetimeRange,
+		"timestamp": *opts.TimestampRange,
+		"year":      *opts.YearRange,
+	}
+	for dataType, value := range flags {
+		r := getters.DefaultTemporalRange(dataType)
+		if value != "" {
+			var err error
+			if r, err = getters.ParseTemporalRange(dataType, value); err != nil {
+				return nil, [2]time.Duration{}, err
+			}
+		}
+		r.Recent = *opts.RecentRatio
+		ranges[dataType] = r
+	}
+
+	min, max := getters.DefaultTimeRange()
+	if *opts.TimeRange != "" {
+		var err error
+		if min, max, err = getters.ParseTimeRange(*opts.TimeRange); err != nil {
+			return nil, [2]time.Duration{}, err
+		}
+	}
+	return ranges, [2]time.Duration{min, max}, nil
+}
+
+// getTables returns the tables to be loaded, in the order they must be loaded, and the number of
+// rows to insert in each table
+func getTables(db *sql.DB) ([]*tableparser.Table, map[string]int, error) {
+	var ddlTables map[string]*tableparser.Table
+	definitionsFile := *opts.DDLFile
+	if *opts.DDLFile != "" {
+		var err error
+		if ddlTables, err = loadDDLFile(*opts.DDLFile, *opts.Schema); err != nil {
+			return nil, nil, err
+		}
+	}
+	if *opts.TableDefinition != "" {
+		definitionsFile = *opts.TableDefinition
+		var err error
+		if ddlTables, err = loadTableDefinitions(*opts.TableDefinition, *opts.Schema); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	tableNames := []string{*opts.TableName}
+	if opts.Command != "table" {
+		tableNames = *opts.Tables
+		if len(tableNames) == 0 && ddlTables != nil {
+			for name := range ddlTables {
+				tableNames = append(tableNames, name)
+			}
+			sort.Strings(tableNames)
+		} else if len(tableNames) == 0 {
+			var err error
+			if tableNames, err = tableparser.GetTableNames(db, *opts.Schema); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	tables := []*tableparser.Table{}
+	rows := make(map[string]int)
+	for _, name := range tableNames {
+		var table *tableparser.Table
+		if ddlTables != nil {
+			var ok bool
+			if table, ok = ddlTables[name]; !ok {
+				return nil, nil, fmt.Errorf("table %s.%s is not defined in %s", *opts.Schema, name, definitionsFile)
+			}
+		} else {
+			var err error
+			if table, err = tableparser.NewTable(db, *opts.Schema, name); err != nil {
+				return nil, nil, fmt.Errorf("cannot get table %s struct: %s", name, err)
+			}
+		}
+		tables = append(tables, table)
+		rows[table.Name] = *opts.Rows
+	}
+
+	for name, value := range *opts.TableRows {
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid number of rows %q for table %s", value, name)
+		}
+		if _, ok := rows[name]; !ok {
+			return nil, nil, fmt.Errorf("table %s in --table-rows is not in the list of tables to load", name)
+		}
+		rows[name] = count
+	}
+
+	if len(tables) < 2 {
+		return tables, rows, nil
+	}
+
+	tables, cyclic := tableparser.SortByDependencies(tables)
+	if len(cyclic) > 0 {
+		log.Warnf("These tables have circular foreign keys dependencies. Samples for their foreign keys fields might be" +
+			" empty, so nullable fields get NULLs and tables having NOT NULL fields referencing empty tables are skipped:")
+		for _, t := range cyclic {
+			log.Warnf("  %s", t.Name)
+		}
+	}
+	names := []string{}
+	for _, t := range tables {
+		names = append(names, t.Name)
+	}
+	log.Debugf("Tables load order: %s", strings.Join(names, ", "))
+
+	return tables, rows, nil
+}
+
+// loadDDLFile returns the tables of the schema defined in a file having CREATE TABLE statements,
+// like the ones written by mysqldump --no-data
+func loadDDLFile(filename, schema string) (map[string]*tableparser.Table, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open the DDL file: %s", err)
+	}
+	defer f.Close()
+
+	tables, err := tableparser.ParseDDL(f, schema)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the DDL file %s: %s", filename, err)
+	}
+	return schemaTables(tables, schema), nil
+}
+
+// loadTableDefinitions returns the tables of the schema saved in a JSON file by the describe command
+func loadTableDefinitions(filename, schema string) (map[string]*tableparser.Table, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open the table definition file: %s", err)
+	}
+	defer f.Close()
+
+	tables, err := tableparser.ReadDefinitions(f)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the table definition file %s: %s", filename, err)
+	}
+	return schemaTables(tables, schema), nil
+}
+
+// schemaTables returns the tables of the schema, by name
+func schemaTables(tables []*tableparser.Table, schema string) map[string]*tableparser.Table {
+	m := make(map[string]*tableparser.Table)
+	for _, table := range tables {
+		if table.Schema == schema {
+			m[table.Name] = table
+		}
 	}
+	return m
+}
+
+// describeTables writes the tables definitions as JSON to a file or, if filename is empty, to the
+// standard output
+func describeTables(tables []*tableparser.Table, filename string) error {
+	if filename == "" {
+		return tableparser.WriteDefinitions(os.Stdout, tables)
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := tableparser.WriteDefinitions(f, tables); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	log.Infof("%d table definitions written to %s", len(tables), filename)
+	return nil
+}
+
+// loadTable inserts 'rows' random rows into the table and returns the number of rows inserted
+func loadTable(db *sql.DB, table *tableparser.Table, rows int, semaphores chan bool, valueOpts valueFuncsOptions) (int, error) {
 	log.Debug(pretty.Sprint(table))
 
 	if len(table.Triggers) > 0 {
-		log.Warnf("There are triggers on the %s table that might affect this process:", *opts.TableName)
+		log.Warnf("There are triggers on the %s table that might affect this process:", table.Name)
 		for _, t := range table.Triggers {
 			log.Warnf("Trigger %q, %s %s", t.Trigger, t.Timing, t.Event)
 			log.Warnf("Statement: %s", t.Statement)
 		}
 	}
 
-	if *opts.Rows < 1 {
-		db.Close() // golint:noerror
-		log.Warnf("Number of rows < 1. There is nothing to do. Exiting")
-		os.Exit(1)
+	bulkSize := *opts.BulkSize
+	if bulkSize > rows {
+		bulkSize = rows
 	}
-
-	if *opts.BulkSize > *opts.Rows {
-		*opts.BulkSize = *opts.Rows
+	if bulkSize < 1 {
+		bulkSize = defaultBulkSize
 	}
-	if *opts.BulkSize < 1 {
-		*opts.BulkSize = defaultBulkSize
+
+	if valueOpts.skipDefaults {
+		table = withoutDefaults(table)
 	}
 
-	if opts.MaxThreads == nil {
-		*opts.MaxThreads = runtime.NumCPU() * 10
+	rowValues, err := makeRowValues(db, table, rows, valueOpts)
+	if err != nil {
+		return 0, err
 	}
 
-	if *opts.MaxThreads < 1 {
-		*opts.MaxThreads = 1
+	bar := uiprogress.AddBar(rows).AppendCompleted().PrependElapsed()
+	bar.PrependFunc(func(b *uiprogress.Bar) string {
+		return table.Name
+	})
+
+	if *opts.OutputFormat != "insert" {
+		return writeTable(table, rows, rowValues, loaddata.Format(*opts.OutputFormat), bar)
 	}
 
-	if !*opts.Print {
-		log.Info("Starting")
+	if *opts.LoadData && !*opts.Print {
+		return loadDataTable(db, table, rows, rowValues, bar)
 	}
 
 	// Example: want 11 rows with bulksize 4:
@@ -194,52 +648,43 @@ func main() {
 	// And then, we need to run this insert once to complete 11 rows
 	// INSERT INTO table (f1, f2) VALUES (?, ?), (?, ?), (?, ?)
 	newLineOnEachRow := false
-	count := *opts.Rows / *opts.BulkSize
-	remainder := *opts.Rows - count**opts.BulkSize
-	semaphores := makeSemaphores(*opts.MaxThreads)
-	rowValues := makeValueFuncs(db, table.Fields, *opts.Samples)
-	log.Debugf("Must run %d bulk inserts having %d rows each", count, *opts.BulkSize)
+	count := rows / bulkSize
+	remainder := rows - count*bulkSize
+	log.Debugf("Must run %d bulk inserts having %d rows each", count, bulkSize)
 
 	runInsertFunc := runInsert
 	if *opts.Print {
-		*opts.MaxThreads = 1
-		*opts.NoProgress = true
 		newLineOnEachRow = true
 		runInsertFunc = func(db *sql.DB, insertQuery string, resultsChan chan int, sem chan bool, wg *sync.WaitGroup) {
 			fmt.Println(insertQuery)
-			resultsChan <- *opts.BulkSize
+			resultsChan <- bulkSize
 			sem <- true
 			wg.Done()
 		}
 	}
 
-	bar := uiprogress.AddBar(*opts.Rows).AppendCompleted().PrependElapsed()
-	if !*opts.NoProgress {
-		uiprogress.Start()
-	}
-
-	okCount, err := run(db, table, bar, semaphores, rowValues, count, *opts.BulkSize, runInsertFunc, newLineOnEachRow)
+	okCount, err := run(db, table, bar, semaphores, rowValues, count, bulkSize, runInsertFunc, newLineOnEachRow)
 	if err != nil {
 		log.Errorln(err)
 	}
 	var okrCount, okiCount int // remainder & individual inserts OK count
 	if remainder > 0 {
-		log.Debugf("Must run 1 extra bulk insert having %d rows, to complete %d rows", remainder, *opts.Rows)
+		log.Debugf("Must run 1 extra bulk insert having %d rows, to complete %d rows", remainder, rows)
 		okrCount, err = run(db, table, bar, semaphores, rowValues, 1, remainder, runInsertFunc, newLineOnEachRow)
 		if err != nil {
 			log.Errorln(err)
 		}
 	}
 
-	// If there were errors and at this point we have less rows than *rows,
+	// If there were errors and at this point we have less rows than rows,
 	// retry adding individual rows (no bulk inserts)
 	totalOkCount := okCount + okrCount
 	retries := 0
-	if totalOkCount < *opts.Rows {
-		log.Debugf("Running extra %d individual inserts (duplicated keys?)", *opts.Rows-totalOkCount)
+	if totalOkCount < rows {
+		log.Debugf("Running extra %d individual inserts (duplicated keys?)", rows-totalOkCount)
 	}
-	for totalOkCount < *opts.Rows && retries < *opts.MaxRetries {
-		okiCount, err = run(db, table, bar, semaphores, rowValues, *opts.Rows-totalOkCount, 1, runInsertFunc, newLineOnEachRow)
+	for totalOkCount < rows && retries < *opts.MaxRetries {
+		okiCount, err = run(db, table, bar, semaphores, rowValues, rows-totalOkCount, 1, runInsertFunc, newLineOnEachRow)
 		if err != nil {
 			log.Errorf("Cannot run extra insert: %s", err)
 		}
@@ -248,11 +693,121 @@ func main() {
 		totalOkCount += okiCount
 	}
 
-	time.Sleep(500 * time.Millisecond) // Let the progress bar to update
-	if !*opts.Print {
-		log.Printf("%d rows inserted", totalOkCount)
+	return totalOkCount, nil
+}
+
+// writeTable writes 'rows' random rows for the table in csv or tsv format and, if requested,
+// the LOAD DATA INFILE statement needed to load them.
+func writeTable(table *tableparser.Table, rows int, rowValues insertValues, format loaddata.Format,
+	bar *uiprogress.Bar) (int, error) {
+	filename := table.Name + "." + string(format)
+	var out io.Writer = os.Stdout
+	if *opts.OutputDir != "" {
+		filename = filepath.Join(*opts.OutputDir, filename)
+		fh, err := os.Create(filename)
+		if err != nil {
+			return 0, err
+		}
+		defer fh.Close()
+		out = fh
+	}
+
+	w := loaddata.NewWriter(out, format)
+	values := make([]string, len(rowValues))
+	for i := 0; i < rows; i++ {
+		for j, value := range rowValues {
+			values[j] = value.String()
+		}
+		if err := w.WriteRow(values); err != nil {
+			return i, fmt.Errorf("cannot write row: %s", err)
+		}
+		bar.Incr()
+	}
+	if err := w.Flush(); err != nil {
+		return rows, fmt.Errorf("cannot write rows: %s", err)
+	}
+
+	if !*opts.LoadDataStmt {
+		return rows, nil
+	}
+
+	if abs, err := filepath.Abs(filename); err == nil && *opts.OutputDir != "" {
+		filename = abs
+	}
+	stmt := loaddata.Statement(format, filename, table.Schema, table.Name, getFieldNames(table.Fields), false)
+	if *opts.OutputDir == "" {
+		fmt.Fprintln(os.Stderr, stmt)
+		return rows, nil
+	}
+	stmtFile := filepath.Join(*opts.OutputDir, table.Name+".sql")
+	if err := ioutil.WriteFile(stmtFile, []byte(stmt+"\n"), 0644); err != nil {
+		return rows, fmt.Errorf("cannot write LOAD DATA INFILE statement into %s: %s", stmtFile, err)
+	}
+
+	return rows, nil
+}
+
+// loadDataTable loads 'rows' random rows into the table using LOAD DATA LOCAL INFILE.
+// Like the bulk inserts, rows lost due to duplicated keys are retried up to --max-retries times.
+func loadDataTable(db *sql.DB, table *tableparser.Table, rows int, rowValues insertValues, bar *uiprogress.Bar) (int, error) {
+	totalOkCount := 0
+	for retries := 0; totalOkCount < rows && retries <= *opts.MaxRetries; retries++ {
+		if retries > 0 {
+			log.Debugf("Loading extra %d rows (duplicated keys?)", rows-totalOkCount)
+		}
+		okCount, err := streamRows(db, table, rows-totalOkCount, rowValues, bar)
+		totalOkCount += okCount
+		bar.Set(totalOkCount)
+		if err != nil {
+			return totalOkCount, err
+		}
 	}
-	db.Close()
+	return totalOkCount, nil
+}
+
+// streamRows generates 'count' rows and streams them to the server using LOAD DATA LOCAL INFILE
+// through a pipe, so rows are sent to the server as they are generated and the whole batch is never
+// held in memory. It returns the number of rows actually loaded.
+func streamRows(db *sql.DB, table *tableparser.Table, count int, rowValues insertValues, bar *uiprogress.Bar) (int, error) {
+	pr, pw := io.Pipe()
+	handlerName := table.Schema + "." + table.Name
+	mysql.RegisterReaderHandler(handlerName, func() io.Reader { return pr })
+	defer mysql.DeregisterReaderHandler(handlerName)
+
+	go func() {
+		pw.CloseWithError(writeRows(pw, count, rowValues, bar))
+	}()
+
+	stmt := loaddata.Statement(loaddata.TSV, "Reader::"+handlerName, table.Schema, table.Name,
+		getFieldNames(table.Fields), true)
+	result, err := db.Exec(stmt)
+	if err != nil {
+		// Unblock the rows generator if the server stopped reading
+		pr.CloseWithError(err)
+		return 0, fmt.Errorf("cannot load data: %s", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("cannot get rows affected after LOAD DATA: %s", err)
+	}
+	return int(rowsAffected), nil
+}
+
+// writeRows writes 'count' rows in the TSV format used by the LOAD DATA statements of streamRows
+func writeRows(out io.Writer, count int, rowValues insertValues, bar *uiprogress.Bar) error {
+	w := loaddata.NewWriter(out, loaddata.TSV)
+	values := make([]string, len(rowValues))
+	for i := 0; i < count; i++ {
+		for j, value := range rowValues {
+			values[j] = value.String()
+		}
+		if err := w.WriteRow(values); err != nil {
+			return err
+		}
+		bar.Incr()
+	}
+	return w.Flush()
 }
 
 func run(db *sql.DB, table *tableparser.Table, bar *uiprogress.Bar, sem chan bool,
@@ -347,7 +902,6 @@ func countRowsOK(count int, bar *uiprogress.Bar) chan int {
 // rowsChan <- [ v1-1, v1-2, v1-3, v2-1, v2-2, v2-3 ]
 // rowsChan <- [ v3-1, v3-2, v3-3, v4-1, v4-2, v4-3 ]
 // rowsChan <- [ v1-5, v5-2, v5-3, v6-1, v6-2, v6-3 ]
-//
 func generateInsertData(count int, values insertValues, rowsChan chan []getter) {
 	for i := 0; i < count; i++ {
 		insertRow := make([]getter, 0, len(values))
@@ -361,8 +915,8 @@ func generateInsertData(count int, values insertValues, rowsChan chan []getter)
 func generateInsertStmt(table *tableparser.Table) string {
 	fields := getFieldNames(table.Fields)
 	query := fmt.Sprintf("INSERT IGNORE INTO %s.%s (%s) VALUES ",
-		backticks(table.Schema),
-		backticks(table.Name),
+		tableparser.Backticks(table.Schema),
+		tableparser.Backticks(table.Name),
 		strings.Join(fields, ","),
 	)
 	return query
@@ -387,60 +941,685 @@ func runInsert(db *sql.DB, insertQuery string, resultsChan chan int, sem chan bo
 	wg.Done()
 }
 
+// fieldSpec returns the generator for a field defined in the generators file or, if there is none and
+// heuristics are enabled, the generator inferred from the field name and comment. Fields having foreign
+// keys don't get inferred generators
+func fieldSpec(field tableparser.Field, valueOpts valueFuncsOptions) (generators.Spec, bool) {
+	if spec, ok := valueOpts.specs.Get(field.TableSchema, field.TableName, field.ColumnName); ok {
+		return spec, true
+	}
+	if !valueOpts.heuristics || field.Constraint != nil {
+		return generators.Spec{}, false
+	}
+	return generators.Infer(field.ColumnName, field.ColumnComment, field.DataType, field.CharacterMaximumLength.Int64)
+}
+
+// printGenerators prints the generators used for the fields of the tables, both user defined and inferred,
+// in the generators file format, so they can be edited and used with --generators-file
+func printGenerators(tables []*tableparser.Table, valueOpts valueFuncsOptions) error {
+	specs := make(generators.Specs)
+	for _, table := range tables {
+		for _, field := range table.Fields {
+			if skipField(field) {
+				continue
+			}
+			if spec, ok := fieldSpec(field, valueOpts); ok {
+				specs[field.TableSchema+"."+field.TableName+"."+field.ColumnName] = spec
+			}
+		}
+	}
+	buf, err := json.MarshalIndent(specs, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Println(string(buf))
+	return err
+}
+
+// profileTables writes the profile of the tables to a file or, if filename is empty, to the standard output
+func profileTables(db *sql.DB, tables []*tableparser.Table, sampleSize int64, filename string) error {
+	prof := make(profile.Profile)
+	for _, table := range tables {
+		log.Infof("Profiling table %s", table.Name)
+		p, err := profile.Collect(db, table, sampleSize)
+		if err != nil {
+			return err
+		}
+		for column, c := range p {
+			prof[column] = c
+		}
+	}
+	if filename == "" {
+		return prof.Write(os.Stdout)
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := prof.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	log.Infof("Profile of %d columns written to %s", len(prof), filename)
+	return nil
+}
+
+// fieldProfile returns the profile of a field. Fields having a generator in the generators file or a
+// foreign key don't use their profiles
+func fieldProfile(field tableparser.Field, valueOpts valueFuncsOptions) (profile.Column, bool) {
+	if valueOpts.profile == nil || field.Constraint != nil {
+		return profile.Column{}, false
+	}
+	if _, ok := valueOpts.specs.Get(field.TableSchema, field.TableName, field.ColumnName); ok {
+		return profile.Column{}, false
+	}
+	return valueOpts.profile.Get(field.TableSchema, field.TableName, field.ColumnName)
+}
+
+// makeRowValues returns the getters for the values of the rows of the table: the getters of the fields,
+// replaced by getters satisfying the CHECK constraints and generating distinct values for the unique keys
+func makeRowValues(conn *sql.DB, table *tableparser.Table, rows int, valueOpts valueFuncsOptions) (insertValues, error) {
+	values, err := makeValueFuncs(conn, table.Fields, valueOpts)
+	if err != nil {
+		return nil, err
+	}
+	fields := insertedFields(table.Fields)
+	if err := checks.Apply(table, fields, values, valueOpts.checksOptions()); err != nil {
+		return nil, err
+	}
+	if err := keys.Make(conn, table, fields, values, rows, valueOpts.keysOptions()); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
 // makeValueFuncs returns an array of functions to generate all the values needed for a single row
-func makeValueFuncs(conn *sql.DB, fields []tableparser.Field, samples int64) insertValues {
+func makeValueFuncs(conn *sql.DB, fields []tableparser.Field, valueOpts valueFuncsOptions) (insertValues, error) {
 	var values []getter
 	for _, field := range fields {
-		if !field.IsNullable && field.ColumnKey == "PRI" && strings.Contains(field.Extra, "auto_increment") {
+		rnd := newColumnRand(valueOpts.seed, field)
+		if skipField(field) {
 			continue
 		}
-		if field.Constraint != nil {
-			samples, err := getSamples(conn, field.Constraint.ReferencedTableSchema,
-				field.Constraint.ReferencedTableName,
-				field.Constraint.ReferencedColumnName,
-				samples, field.DataType)
+		if col, ok := fieldProfile(field, valueOpts); ok {
+			g, err := profile.NewGetter(field, col, rnd)
 			if err != nil {
-				log.Printf("cannot get samples for field %q: %s\n", field.ColumnName, err)
+				log.Printf("cannot use the profile for field %q: %s. Using the default generator\n", field.ColumnName, err)
+			} else {
+				values = append(values, g)
 				continue
 			}
-			values = append(values, getters.NewRandomSample(field.ColumnName, samples, field.IsNullable))
-			continue
 		}
-		maxValue := maxValues["bigint"]
-		if m, ok := maxValues[field.DataType]; ok {
-			maxValue = m
+		if spec, ok := fieldSpec(field, valueOpts); ok {
+			g, err := makeSpecGetter(conn, field, spec, valueOpts, rnd)
+			if err != nil {
+				log.Printf("cannot use the generator for field %q: %s. Using the default generator\n", field.ColumnName, err)
+			} else {
+				values = append(values, g)
+				continue
+			}
+		}
+		if field.Constraint != nil && conn == nil {
+			log.Warnf("Field %q references %s.%s but there is no connection to get samples from it. Using random values",
+				field.ColumnName, field.Constraint.ReferencedTableName, field.Constraint.ReferencedColumnName)
+		} else if field.Constraint != nil {
+			samples, err := fieldSamples(conn, field, valueOpts)
+			if err != nil {
+				return nil, err
+			}
+			if len(samples) == 0 {
+				g, err := emptySamplesGetter(field, valueOpts)
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, g)
+				continue
+			}
+			values = append(values, getters.NewRandomSample(field.ColumnName, samples, field.IsNullable, rnd))
+			continue
 		}
 		switch field.DataType {
 		case "tinyint", "smallint", "mediumint", "int", "integer", "bigint":
-			values = append(values, getters.NewRandomInt(field.ColumnName, maxValue, field.IsNullable))
-		case "float", "decimal", "double":
-			values = append(values, getters.NewRandomDecimal(field.ColumnName,
-				field.NumericPrecision.Int64-field.NumericScale.Int64, field.IsNullable))
+			if field.IsUnsigned() {
+				values = append(values, getters.NewRandomUintRange(field.ColumnName, 0,
+					getters.UintMaxValue(field.DataType), field.IsNullable, rnd))
+				break
+			}
+			min, max := getters.IntRange(field.DataType)
+			values = append(values, getters.NewRandomIntRange(field.ColumnName, min, max, field.IsNullable, rnd))
+		case "decimal":
+			values = append(values, getters.NewRandomDecimal(field.ColumnName, field.NumericPrecision.Int64,
+				field.NumericScale.Int64, field.IsUnsigned(), field.IsNullable, rnd))
+		case "float", "double":
+			bitSize := 64
+			if field.DataType == "float" {
+				bitSize = 32
+			}
+			// float(M,D) & double(M,D) have NUMERIC_SCALE. Plain float & double have it NULL
+			var precision, scale int64
+			if field.NumericScale.Valid {
+				precision, scale = field.NumericPrecision.Int64, field.NumericScale.Int64
+			}
+			values = append(values, getters.NewRandomFloat(field.ColumnName, bitSize, precision, scale,
+				field.IsUnsigned(), field.IsNullable, rnd))
 		case "char", "varchar":
 			values = append(values, getters.NewRandomString(field.ColumnName,
-				field.CharacterMaximumLength.Int64, field.IsNullable))
+				field.CharacterMaximumLength.Int64, field.IsNullable, rnd))
 		case "date":
-			values = append(values, getters.NewRandomDate(field.ColumnName, field.IsNullable))
+			values = append(values, getters.NewRandomDate(field.ColumnName, valueOpts.temporalRange(field.DataType),
+				field.IsNullable, rnd))
 		case "datetime", "timestamp":
-			values = append(values, getters.NewRandomDateTime(field.ColumnName, field.IsNullable))
-		case "tinyblob", "tinytext", "blob", "text", "mediumtext", "mediumblob", "longblob", "longtext":
+			values = append(values, getters.NewRandomDateTime(field.ColumnName, field.DatetimePrecision.Int64,
+				valueOpts.temporalRange(field.DataType), field.IsNullable, rnd))
+		case "tinytext", "text", "mediumtext", "longtext":
 			values = append(values, getters.NewRandomString(field.ColumnName,
-				field.CharacterMaximumLength.Int64, field.IsNullable))
+				field.CharacterMaximumLength.Int64, field.IsNullable, rnd))
+		case "tinyblob", "blob", "mediumblob", "longblob":
+			maxSize := field.CharacterOctetLength.Int64
+			if maxSize > valueOpts.maxBlobSize {
+				maxSize = valueOpts.maxBlobSize
+			}
+			values = append(values, getters.NewRandomBinary(field.ColumnName, 0, maxSize, 0, field.IsNullable, rnd))
 		case "time":
-			values = append(values, getters.NewRandomTime(field.IsNullable))
+			min, max := valueOpts.timeMinMax()
+			values = append(values, getters.NewRandomTime(field.DatetimePrecision.Int64, min, max, field.IsNullable, rnd))
 		case "year":
-			values = append(values, getters.NewRandomIntRange(field.ColumnName, int64(time.Now().Year()-1),
-				int64(time.Now().Year()), field.IsNullable))
-		case "enum", "set":
-			values = append(values, getters.NewRandomEnum(field.SetEnumVals, field.IsNullable))
-		case "binary", "varbinary":
-			values = append(values, getters.NewRandomBinary(field.ColumnName, field.CharacterMaximumLength.Int64, field.IsNullable))
+			values = append(values, getters.NewRandomYear(field.ColumnName, valueOpts.temporalRange(field.DataType),
+				field.IsNullable, rnd))
+		case "enum":
+			values = append(values, getters.NewRandomEnum(field.SetEnumVals, field.IsNullable, rnd))
+		case "set":
+			values = append(values, getters.NewRandomSet(field.ColumnName, field.SetEnumVals, 0,
+				int64(len(field.SetEnumVals)), field.IsNullable, rnd))
+		case "binary":
+			size := field.CharacterOctetLength.Int64
+			values = append(values, getters.NewRandomBinary(field.ColumnName, 0, size, size, field.IsNullable, rnd))
+		case "varbinary":
+			values = append(values, getters.NewRandomBinary(field.ColumnName, 0,
+				field.CharacterOctetLength.Int64, 0, field.IsNullable, rnd))
+		case "json":
+			values = append(values, getters.NewRandomJSON(field.ColumnName, nil, 0, nil, 0, field.IsNullable, rnd))
+		case "bit":
+			// NUMERIC_PRECISION has the number of bits
+			values = append(values, getters.NewRandomBit(field.ColumnName, field.NumericPrecision.Int64,
+				field.IsNullable, rnd))
+		case "geometry", "point", "linestring", "polygon", "multipoint", "multilinestring", "multipolygon",
+			"geometrycollection", "geomcollection":
+			values = append(values, getters.NewRandomGeometry(field.ColumnName, field.DataType, srid(field),
+				valueOpts.bbox, field.IsNullable, rnd))
 		default:
 			log.Printf("cannot get field type: %s: %s\n", field.ColumnName, field.DataType)
 		}
 	}
 
-	return values
+	return values, nil
+}
+
+// fieldSamples returns the samples of the column referenced by the foreign key of the field. There are
+// no samples if the referenced table is empty
+func fieldSamples(conn *sql.DB, field tableparser.Field, valueOpts valueFuncsOptions) ([]interface{}, error) {
+	samples, err := getSamples(conn, field.Constraint.ReferencedTableSchema,
+		field.Constraint.ReferencedTableName,
+		field.Constraint.ReferencedColumnName,
+		valueOpts.samples, field.DataType, valueOpts.seed)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get samples for field %q: %s", field.ColumnName, err)
+	}
+	return samples, nil
+}
+
+// emptySamplesGetter returns the getter for a foreign key field referencing an empty table, like a self
+// reference or a table in a foreign keys cycle: NULL for nullable fields. There are no valid values for
+// the other fields, so their table cannot be loaded
+func emptySamplesGetter(field tableparser.Field, valueOpts valueFuncsOptions) (getter, error) {
+	c := field.Constraint
+	if field.IsNullable {
+		log.Warnf("Field %q references the empty table %s.%s. Using NULL", field.ColumnName,
+			c.ReferencedTableSchema, c.ReferencedTableName)
+		return getters.NewConstant(nil), nil
+	}
+	for _, table := range valueOpts.tables {
+		if table.Schema != field.TableSchema || table.Name != field.TableName {
+			continue
+		}
+		if cycle := tableparser.DependencyCycle(valueOpts.tables, table); cycle != nil {
+			return nil, fmt.Errorf("field %q references the empty table %s.%s and doesn't accept NULLs. The "+
+				"tables have circular foreign keys (%s) so one of them must be loaded first", field.ColumnName,
+				c.ReferencedTableSchema, c.ReferencedTableName, strings.Join(cycle, " -> "))
+		}
+	}
+	return nil, fmt.Errorf("field %q references the empty table %s.%s and doesn't accept NULLs", field.ColumnName,
+		c.ReferencedTableSchema, c.ReferencedTableName)
+}
+
+// makeSpecGetter returns a getter built from the user defined generator spec for the field
+func makeSpecGetter(conn *sql.DB, field tableparser.Field, spec generators.Spec, valueOpts valueFuncsOptions,
+	rnd *rand.Rand) (getter, error) {
+	var g getter
+	// If there is a null ratio in the spec, NULLs are handled by the NullRatio wrapper
+	allowNull := field.IsNullable && spec.NullRatio == nil
+
+	switch spec.Generator {
+	case "int":
+		if field.IsUnsigned() {
+			min, err := spec.Min.Uint64(0)
+			if err != nil {
+				return nil, fmt.Errorf("invalid min value %q: %s", spec.Min, err)
+			}
+			typeMax := getters.UintMaxValue(field.DataType)
+			max, err := spec.Max.Uint64(typeMax)
+			if err != nil {
+				return nil, fmt.Errorf("invalid max value %q: %s", spec.Max, err)
+			}
+			if min > max {
+				return nil, fmt.Errorf("min (%d) is greater than max (%d)", min, max)
+			}
+			if getters.IsIntegerType(field.DataType) && max > typeMax {
+				return nil, fmt.Errorf("max value %d is out of the %s unsigned range 0 ~ %d", max, field.DataType, typeMax)
+			}
+			if spec.Distribution == nil {
+				g = getters.NewRandomUintRange(field.ColumnName, min, max, allowNull, rnd)
+				break
+			}
+			dist, err := makeDistribution(spec.Distribution, max-min, func(p generators.Param) (float64, error) {
+				v, err := p.Uint64(0)
+				return float64(v) - float64(min), err
+			}, paramFloat)
+			if err != nil {
+				return nil, err
+			}
+			g = getters.NewDistributedUintRange(field.ColumnName, min, max, dist, allowNull, rnd)
+			break
+		}
+		typeMin, typeMax := getters.IntRange(field.DataType)
+		min, err := spec.Min.Int64(typeMin)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min value %q: %s", spec.Min, err)
+		}
+		max, err := spec.Max.Int64(typeMax)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max value %q: %s", spec.Max, err)
+		}
+		if min > max {
+			return nil, fmt.Errorf("min (%d) is greater than max (%d)", min, max)
+		}
+		if getters.IsIntegerType(field.DataType) && (min < typeMin || max > typeMax) {
+			return nil, fmt.Errorf("range %d ~ %d is out of the %s range %d ~ %d", min, max, field.DataType,
+				typeMin, typeMax)
+		}
+		if spec.Distribution == nil {
+			g = getters.NewRandomIntRange(field.ColumnName, min, max, allowNull, rnd)
+			break
+		}
+		dist, err := makeDistribution(spec.Distribution, uint64(max)-uint64(min), func(p generators.Param) (float64, error) {
+			v, err := p.Int64(0)
+			return float64(v) - float64(min), err
+		}, paramFloat)
+		if err != nil {
+			return nil, err
+		}
+		g = getters.NewDistributedIntRange(field.ColumnName, min, max, dist, allowNull, rnd)
+	case "string":
+		length := spec.Length
+		if length == 0 {
+			length = field.CharacterMaximumLength.Int64
+		}
+		g = getters.NewRandomString(field.ColumnName, length, allowNull, rnd)
+	case "date":
+		g = getters.NewRandomDate(field.ColumnName, valueOpts.temporalRange("date"), allowNull, rnd)
+	case "date_in_range":
+		switch field.DataType {
+		case "date", "datetime", "timestamp":
+		default:
+			return nil, fmt.Errorf("the date_in_range generator cannot be used for %s fields", field.DataType)
+		}
+		if spec.Distribution == nil {
+			var err error
+			g, err = getters.NewRandomDateInRange(field.ColumnName, field.DataType, field.DatetimePrecision.Int64,
+				string(spec.Min), string(spec.Max), allowNull, rnd)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+		r, err := getters.ParseDateInRangeBounds(field.DataType, string(spec.Min), string(spec.Max))
+		if err != nil {
+			return nil, err
+		}
+		// The positions of the distribution are the seconds since the min date
+		dist, err := makeDistribution(spec.Distribution, uint64(r.Max.Unix()-r.Min.Unix()),
+			func(p generators.Param) (float64, error) {
+				t, err := getters.ParseDateTime(string(p))
+				return float64(t.Unix() - r.Min.Unix()), err
+			}, paramDuration)
+		if err != nil {
+			return nil, err
+		}
+		g = getters.NewDistributedDateInRange(field.ColumnName, field.DataType, field.DatetimePrecision.Int64, r,
+			dist, allowNull, rnd)
+	case "values":
+		values := spec.Values
+		if len(values) == 0 && field.DataType == "enum" {
+			values = field.SetEnumVals
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("the values generator needs a non empty values list for %s fields", field.DataType)
+		}
+		if spec.Distribution == nil {
+			g = getters.NewRandomEnum(values, allowNull, rnd)
+			break
+		}
+		samples := make([]interface{}, len(values))
+		for i, v := range values {
+			samples[i] = v
+		}
+		dist, err := makeDistribution(spec.Distribution, uint64(len(samples)-1), paramFloat, paramFloat)
+		if err != nil {
+			return nil, err
+		}
+		g = getters.NewDistributedSample(field.ColumnName, samples, dist, allowNull, rnd)
+	case "samples":
+		if field.Constraint == nil {
+			return nil, fmt.Errorf("the samples generator can only be used for foreign key fields")
+		}
+		if conn == nil {
+			return nil, fmt.Errorf("the samples generator needs a connection to get samples from %s.%s",
+				field.Constraint.ReferencedTableName, field.Constraint.ReferencedColumnName)
+		}
+		samples, err := fieldSamples(conn, field, valueOpts)
+		if err != nil {
+			return nil, err
+		}
+		if len(samples) == 0 {
+			return nil, fmt.Errorf("the referenced table %s.%s is empty", field.Constraint.ReferencedTableSchema,
+				field.Constraint.ReferencedTableName)
+		}
+		if spec.Distribution == nil {
+			g = getters.NewRandomSample(field.ColumnName, samples, allowNull, rnd)
+			break
+		}
+		dist, err := makeDistribution(spec.Distribution, uint64(len(samples)-1), paramFloat, paramFloat)
+		if err != nil {
+			return nil, err
+		}
+		g = getters.NewDistributedSample(field.ColumnName, samples, dist, allowNull, rnd)
+	case "set":
+		if field.DataType != "set" {
+			return nil, fmt.Errorf("the set generator cannot be used for %s fields", field.DataType)
+		}
+		members := field.SetEnumVals
+		if len(spec.Values) > 0 {
+			if err := checkSetMembers(spec.Values, field.SetEnumVals); err != nil {
+				return nil, err
+			}
+			members = spec.Values
+		}
+		min, err := spec.Min.Int64(0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min value %q: %s", spec.Min, err)
+		}
+		max, err := spec.Max.Int64(int64(len(members)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid max value %q: %s", spec.Max, err)
+		}
+		if max > int64(len(members)) {
+			return nil, fmt.Errorf("max number of members %d is greater than the number of members %d", max, len(members))
+		}
+		g = getters.NewRandomSet(field.ColumnName, members, min, max, allowNull, rnd)
+	case "binary":
+		maxSize := field.CharacterOctetLength.Int64
+		if spec.Length > 0 {
+			maxSize = spec.Length
+		}
+		min, err := spec.Min.Int64(0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min value %q: %s", spec.Min, err)
+		}
+		max, err := spec.Max.Int64(maxSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max value %q: %s", spec.Max, err)
+		}
+		if max > field.CharacterOctetLength.Int64 {
+			return nil, fmt.Errorf("max size %d is greater than the field size %d", max, field.CharacterOctetLength.Int64)
+		}
+		var padSize int64
+		if field.DataType == "binary" {
+			padSize = field.CharacterOctetLength.Int64
+		}
+		g = getters.NewRandomBinary(field.ColumnName, min, max, padSize, allowNull, rnd)
+	case "json":
+		var schema *getters.JSONSchema
+		var err error
+		if len(spec.Schema) > 0 {
+			schema, err = getters.NewJSONSchema(spec.Schema)
+		} else if len(spec.Sample) > 0 {
+			schema, err = getters.NewJSONSchemaFromSample(spec.Sample)
+		}
+		if err != nil {
+			return nil, err
+		}
+		g = getters.NewRandomJSON(field.ColumnName, schema, spec.Depth, spec.Keys, spec.ArraySize, allowNull, rnd)
+	case "geometry":
+		if !isSpatialType(field.DataType) {
+			return nil, fmt.Errorf("the geometry generator cannot be used for %s fields", field.DataType)
+		}
+		bbox := valueOpts.bbox
+		if len(spec.BoundingBox) == 4 {
+			bbox = getters.BoundingBox{MinX: spec.BoundingBox[0], MinY: spec.BoundingBox[1],
+				MaxX: spec.BoundingBox[2], MaxY: spec.BoundingBox[3]}
+		}
+		g = getters.NewRandomGeometry(field.ColumnName, field.DataType, srid(field), bbox, allowNull, rnd)
+	case "sequence":
+		if !getters.IsIntegerType(field.DataType) {
+			return nil, fmt.Errorf("the sequence generator cannot be used for %s fields", field.DataType)
+		}
+		if field.IsUnsigned() {
+			max, err := keys.MaxUintValue(conn, field)
+			if err != nil {
+				return nil, err
+			}
+			start, err := spec.Min.Uint64(max + 1)
+			if err != nil {
+				return nil, fmt.Errorf("invalid min value %q: %s", spec.Min, err)
+			}
+			g = getters.NewUintSequence(field.ColumnName, start)
+			break
+		}
+		max, err := keys.MaxValue(conn, field)
+		if err != nil {
+			return nil, err
+		}
+		start, err := spec.Min.Int64(max + 1)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min value %q: %s", spec.Min, err)
+		}
+		g = getters.NewSequence(field.ColumnName, start)
+	case "uuid":
+		switch {
+		case (field.DataType == "binary" || field.DataType == "varbinary") && field.CharacterOctetLength.Int64 >= 16:
+			g = getters.NewRandomUUID(field.ColumnName, spec.Version, true, rnd)
+		case (field.DataType == "char" || field.DataType == "varchar") && field.CharacterMaximumLength.Int64 >= 36:
+			g = getters.NewRandomUUID(field.ColumnName, spec.Version, false, rnd)
+		default:
+			return nil, fmt.Errorf("the uuid generator needs a char(36) or binary(16) field")
+		}
+	case "fake":
+		switch field.DataType {
+		case "char", "varchar", "tinytext", "text", "mediumtext", "longtext":
+		default:
+			return nil, fmt.Errorf("the fake generator cannot be used for %s fields", field.DataType)
+		}
+		var err error
+		g, err = getters.NewRandomFake(field.ColumnName, spec.Kind, field.CharacterMaximumLength.Int64, allowNull, rnd)
+		if err != nil {
+			return nil, err
+		}
+	case "ulid":
+		if (field.DataType != "char" && field.DataType != "varchar") || field.CharacterMaximumLength.Int64 < 26 {
+			return nil, fmt.Errorf("the ulid generator needs a char(26) field")
+		}
+		g = getters.NewRandomULID(field.ColumnName, rnd)
+	default:
+		return nil, fmt.Errorf("unknown generator %q", spec.Generator)
+	}
+
+	if spec.NullRatio != nil {
+		if !field.IsNullable && *spec.NullRatio > 0 {
+			return nil, fmt.Errorf("null_ratio is %v but the field doesn't accept NULLs", *spec.NullRatio)
+		}
+		g = getters.NewNullRatio(g, *spec.NullRatio, rnd)
+	}
+	return g, nil
+}
+
+// makeDistribution returns the distribution for a range of values mapped to the [0, max] positions. position
+// converts the mean of the normal distribution to a position and length converts its standard deviation to a
+// number of positions. By default, the normal distribution is centered in the range and 99.7% of the values
+// are in the range.
+func makeDistribution(d *generators.Distribution, max uint64, position, length func(generators.Param) (float64, error)) (
+	getters.Distribution, error) {
+	switch d.Type {
+	case "zipf":
+		s := d.S
+		if s == 0 {
+			s = 1.1
+		}
+		return getters.NewZipf(s, d.Reverse), nil
+	case "normal":
+		mean, stddev := float64(max)/2, float64(max)/6
+		var err error
+		if d.Mean != "" {
+			if mean, err = position(d.Mean); err != nil {
+				return nil, fmt.Errorf("invalid mean %q: %s", d.Mean, err)
+			}
+		}
+		if d.StdDev != "" {
+			if stddev, err = length(d.StdDev); err != nil {
+				return nil, fmt.Errorf("invalid stddev %q: %s", d.StdDev, err)
+			}
+			if stddev <= 0 {
+				return nil, fmt.Errorf("invalid stddev %q: it must be positive", d.StdDev)
+			}
+		}
+		return getters.NewNormal(mean, stddev), nil
+	case "exponential":
+		rate := d.Rate
+		if rate == 0 {
+			rate = 5
+		}
+		return getters.NewExponential(rate, d.Reverse), nil
+	case "weighted":
+		if uint64(len(d.Weights)-1) > max {
+			return nil, fmt.Errorf("there are %d weights for %d values", len(d.Weights), max+1)
+		}
+		return getters.NewWeights(d.Weights), nil
+	}
+	return getters.NewUniform(), nil
+}
+
+// paramFloat parses a distribution parameter given as a number
+func paramFloat(p generators.Param) (float64, error) {
+	return strconv.ParseFloat(string(p), 64)
+}
+
+// paramDuration parses a distribution parameter given as a duration like 30d or 12h, and returns it in seconds
+func paramDuration(p generators.Param) (float64, error) {
+	s := strings.TrimPrefix(string(p), "+")
+	ref, _ := getters.ParseDateTime("now")
+	t, err := getters.ParseDateTime("+" + s)
+	if err != nil {
+		return 0, fmt.Errorf("durations must be a number followed by a unit (s, m, h, d, w or y), like 30d")
+	}
+	return t.Sub(ref).Seconds(), nil
+}
+
+// checkSetMembers returns an error if any of the values is not a member of the set column. Like MySQL,
+// members are compared without case
+func checkSetMembers(values, members []string) error {
+	valid := make(map[string]bool)
+	for _, m := range members {
+		valid[strings.ToLower(m)] = true
+	}
+	for _, v := range values {
+		if !valid[strings.ToLower(v)] {
+			return fmt.Errorf("%q is not a member of the set. Members are: %s", v, strings.Join(members, ", "))
+		}
+	}
+	return nil
+}
+
+// isSpatialType returns true if the data type is one of the spatial data types
+func isSpatialType(dataType string) bool {
+	switch dataType {
+	case "geometry", "point", "linestring", "polygon", "multipoint", "multilinestring", "multipolygon",
+		"geometrycollection", "geomcollection":
+		return true
+	}
+	return false
+}
+
+// srid returns the SRID of a spatial field. Fields without an SRID attribute (and all fields
+// in MySQL 5.7) accept values having any SRID; 0 is used for them.
+func srid(field tableparser.Field) int64 {
+	if !field.SrsID.Valid {
+		return 0
+	}
+	id, _ := strconv.ParseInt(field.SrsID.String, 10, 64)
+	return id
+}
+
+// newColumnRand returns the random source of the field
+func newColumnRand(seed int64, field tableparser.Field) *rand.Rand {
+	return getters.NewColumnRand(seed, field.TableSchema, field.TableName, field.ColumnName)
+}
+
+// skipField returns true if the field must not be included in the INSERT statements: auto increment
+// primary keys, generated columns and invisible columns.
+func skipField(field tableparser.Field) bool {
+	extra := strings.ToUpper(field.Extra)
+	if !field.IsNullable && field.ColumnKey == "PRI" && strings.Contains(extra, "AUTO_INCREMENT") {
+		return true
+	}
+	if field.GenerationExpression != "" || strings.Contains(extra, "VIRTUAL GENERATED") ||
+		strings.Contains(extra, "STORED GENERATED") {
+		return true
+	}
+	return strings.Contains(extra, "INVISIBLE")
+}
+
+// hasDefault returns true if the field has a default value or a default expression, like CURRENT_TIMESTAMP
+func hasDefault(field tableparser.Field) bool {
+	return field.ColumnDefault.Valid || strings.Contains(strings.ToUpper(field.Extra), "DEFAULT_GENERATED")
+}
+
+// withoutDefaults returns a copy of the table without the fields having a default value, so they are
+// left out of the INSERT statements and the server's defaults apply
+func withoutDefaults(table *tableparser.Table) *tableparser.Table {
+	t := *table
+	t.Fields = []tableparser.Field{}
+	for _, field := range table.Fields {
+		if hasDefault(field) {
+			log.Debugf("Skipping field %s having a default value", field.ColumnName)
+			continue
+		}
+		t.Fields = append(t.Fields, field)
+	}
+	return &t
+}
+
+// insertedFields returns the fields included in the INSERT statements, having a getter in the values
+// returned by makeValueFuncs
+func insertedFields(fields []tableparser.Field) []tableparser.Field {
+	inserted := []tableparser.Field{}
+	for _, field := range fields {
+		if !skipField(field) && isSupportedType(field.DataType) {
+			inserted = append(inserted, field)
+		}
+	}
+	return inserted
 }
 
 func getFieldNames(fields []tableparser.Field) []string {
@@ -449,16 +1628,17 @@ func getFieldNames(fields []tableparser.Field) []string {
 		if !isSupportedType(field.DataType) {
 			continue
 		}
-		if !field.IsNullable && field.ColumnKey == "PRI" &&
-			strings.Contains(field.Extra, "auto_increment") {
+		if skipField(field) {
 			continue
 		}
-		fieldNames = append(fieldNames, backticks(field.ColumnName))
+		fieldNames = append(fieldNames, tableparser.Backticks(field.ColumnName))
 	}
 	return fieldNames
 }
 
-func getSamples(conn *sql.DB, schema, table, field string, samples int64, dataType string) ([]interface{}, error) {
+// getSamples returns random samples from a field in a table. If seed is not 0, it is used to seed
+// the RAND() function so the same samples are returned if the table has the same data.
+func getSamples(conn *sql.DB, schema, table, field string, samples int64, dataType string, seed int64) ([]interface{}, error) {
 	var count int64
 	var query string
 
@@ -470,8 +1650,12 @@ func getSamples(conn *sql.DB, schema, table, field string, samples int64, dataTy
 	if count < samples {
 		query = fmt.Sprintf("SELECT `%s` FROM `%s`.`%s`", field, schema, table)
 	} else {
-		query = fmt.Sprintf("SELECT `%s` FROM `%s`.`%s` WHERE RAND() <= .3 LIMIT %d",
-			field, schema, table, samples)
+		randFunc := "RAND()"
+		if seed != 0 {
+			randFunc = fmt.Sprintf("RAND(%d)", seed)
+		}
+		query = fmt.Sprintf("SELECT `%s` FROM `%s`.`%s` WHERE %s <= .3 LIMIT %d",
+			field, schema, table, randFunc, samples)
 	}
 
 	rows, err := conn.Query(query)
@@ -491,13 +1675,14 @@ func getSamples(conn *sql.DB, schema, table, field string, samples int64, dataTy
 			var v int64
 			err = rows.Scan(&v)
 			val = v
-		case "char", "varchar", "blob", "text", "mediumtext",
-			"mediumblob", "longblob", "longtext":
+		case "char", "varchar", "tinytext", "text", "mediumtext", "longtext", "json":
 			var v string
 			err = rows.Scan(&v)
 			val = v
-		case "binary", "varbinary":
-			var v []rune
+		// bit values are returned as big endian binary strings. Using them as []byte they are
+		// quoted as hex literals, which can be assigned to bit fields
+		case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob", "bit":
+			var v []byte
 			err = rows.Scan(&v)
 			val = v
 		case "float", "decimal", "double":
@@ -520,13 +1705,6 @@ func getSamples(conn *sql.DB, schema, table, field string, samples int64, dataTy
 	return values, nil
 }
 
-func backticks(val string) string {
-	if strings.HasPrefix(val, "`") && strings.HasSuffix(val, "`") {
-		return url.QueryEscape(val)
-	}
-	return "`" + url.QueryEscape(val) + "`"
-}
-
 func isSupportedType(fieldType string) bool {
 	supportedTypes := map[string]bool{
 		"tinyint":    true,
@@ -557,6 +1735,18 @@ func isSupportedType(fieldType string) bool {
 		"varbinary":  true,
 		"enum":       true,
 		"set":        true,
+		"json":       true,
+		"bit":        true,
+		// Spatial types
+		"geometry":           true,
+		"point":              true,
+		"linestring":         true,
+		"polygon":            true,
+		"multipoint":         true,
+		"multilinestring":    true,
+		"multipolygon":       true,
+		"geometrycollection": true,
+		"geomcollection":     true,
 	}
 	_, ok := supportedTypes[fieldType]
 	return ok
@@ -566,31 +1756,109 @@ func processCliParams() (*cliOptions, error) {
 	app := kingpin.New("mysql_random_data_loader", "MySQL Random Data Loader")
 
 	opts := &cliOptions{
-		app:        app,
+		app: app,
+		BoundingBox: app.Flag("bounding-box", "Area for the coordinates of spatial fields values, as minX,minY,maxX,maxY."+
+			" For geographic SRSs x is the longitude and y is the latitude").Default("-180,-90,180,90").String(),
 		BulkSize:   app.Flag("bulk-size", "Number of rows per insert statement").Default(fmt.Sprintf("%d", defaultBulkSize)).Int(),
 		ConfigFile: app.Flag("config-file", "MySQL config file").Default(expandHomeDir(defaultConfigFile)).String(),
-		Debug:      app.Flag("debug", "Log debugging information").Bool(),
-		Factor:     app.Flag("fk-samples-factor", "Percentage used to get random samples for foreign keys fields").Default("0.3").Float64(),
-		Host:       app.Flag("host", "Host name/IP").Short('h').String(),
+		DateRange: app.Flag("date-range", "Range for date fields values, as min,max (YYYY-MM-DD)."+
+			" Default: 1000-01-01,9999-12-31").String(),
+		DatetimeRange: app.Flag("datetime-range", "Range for datetime fields values, as min,max (YYYY-MM-DD[ HH:MM:SS])."+
+			" Default: 1000-01-01 00:00:00,9999-12-31 23:59:59").String(),
+		DDLFile: app.Flag("ddl-file", "File having the CREATE TABLE statements of the tables, like the output of"+
+			" mysqldump --no-data. The tables structure is read from this file instead of the server, and no"+
+			" connection is needed when using --print or --output-format=csv|tsv").String(),
+		Debug:          app.Flag("debug", "Log debugging information").Bool(),
+		Factor:         app.Flag("fk-samples-factor", "Percentage used to get random samples for foreign keys fields").Default("0.3").Float64(),
+		GeneratorsFile: app.Flag("generators-file", "JSON or YAML file having per column generators definitions").String(),
+		Heuristics: app.Flag("heuristics", "Infer the generators of string and date fields from their names and"+
+			" comments, like email or created_at").Default("false").Bool(),
+		Host: app.Flag("host", "Host name/IP").Short('h').String(),
+		MaxBlobSize: app.Flag("max-blob-size", "Maximum size in bytes for blob fields values. Values sizes are uniformly"+
+			" distributed between 0 and the minimum of this value and the field type limit").Default("100").Int64(),
 		MaxRetries: app.Flag("max-retries", "Number of rows to insert").Default("100").Int(),
 		MaxThreads: app.Flag("max-threads", "Maximum number of threads to run inserts").Default("1").Int(),
+		LoadData: app.Flag("load-data", "Load the rows streaming them with LOAD DATA LOCAL INFILE instead of running"+
+			" INSERT statements. The server must have local_infile enabled").Bool(),
+		LoadDataStmt: app.Flag("load-data-stmt", "Also write the LOAD DATA INFILE statement needed to load the csv/tsv output."+
+			" It is written to <output-dir>/<table>.sql or to the standard error if --output-dir was not specified").Bool(),
 		NoProgress: app.Flag("no-progress", "Show progress bar").Default("false").Bool(),
-		Pass:       app.Flag("password", "Password").Short('p').String(),
-		Port:       app.Flag("port", "Port").Short('P').Int(),
-		Print:      app.Flag("print", "Print queries to the standard output instead of inserting them into the db").Bool(),
-		Samples:    app.Flag("max-fk-samples", "Maximum number of samples for foreign keys fields").Default("100").Int64(),
-		User:       app.Flag("user", "User").Short('u').String(),
-		Version:    app.Flag("version", "Show version and exit").Bool(),
-
-		Schema:    app.Arg("database", "Database").Required().String(),
-		TableName: app.Arg("table", "Table").Required().String(),
-		Rows:      app.Arg("rows", "Number of rows to insert").Required().Int(),
+		OutputDir: app.Flag("output-dir", "Directory for the csv/tsv output. Rows are written to <output-dir>/<table>.<format>."+
+			" Default: standard output").String(),
+		OutputFormat: app.Flag("output-format", "Output format. insert: insert rows into the table (or print the INSERT statements"+
+			" if --print was specified). csv/tsv: write the rows in a format suitable for LOAD DATA INFILE").
+			Default("insert").Enum("insert", "csv", "tsv"),
+		Pass: app.Flag("password", "Password").Short('p').String(),
+		Port: app.Flag("port", "Port").Short('P').Int(),
+		ProfileFile: app.Flag("profile-file", "Profile file written by the profile command. Fields get values having the"+
+			" distributions in the profile").String(),
+		Print: app.Flag("print", "Print queries to the standard output instead of inserting them into the db").Bool(),
+		RecentRatio: app.Flag("recent-ratio", "Ratio (0 ~ 1) of date, datetime, timestamp and year values generated"+
+			" between the reference time - 1 year and the reference time. Default: 0 (uniformly distributed in the range)").
+			Default("0").Float64(),
+		ReferenceTime: app.Flag("reference-time", "Date and time (YYYY-MM-DD HH:MM:SS) used as the current time for dates relative to now."+
+			" Default: now").String(),
+		Samples: app.Flag("max-fk-samples", "Maximum number of samples for foreign keys fields").Default("100").Int64(),
+		Seed: app.Flag("seed", "Seed for the random values generator. Runs using the same seed (and reference time) generate the same values."+
+			" Default: random").Int64(),
+		TableDefinition: app.Flag("table-definition", "JSON file having the tables definitions written by the describe"+
+			" command. The tables structure is read from this file instead of the server, and no connection is needed"+
+			" when using --print or --output-format=csv|tsv").String(),
+		TimeRange: app.Flag("time-range", "Range for time fields values, as min,max ([-]HHH:MM:SS)."+
+			" Default: -838:59:59,838:59:59").String(),
+		TimestampRange: app.Flag("timestamp-range", "Range for timestamp fields values, as min,max (YYYY-MM-DD[ HH:MM:SS]), in UTC."+
+			" Default: 1970-01-01 00:00:01,2038-01-19 03:14:07").String(),
+		SkipDefaults: app.Flag("skip-defaults", "Leave the columns having a default value (or a default expression) out of"+
+			" the INSERT statements so the server's defaults apply").Bool(),
+		User:    app.Flag("user", "User").Short('u').String(),
+		Version: app.Flag("version", "Show version and exit").Bool(),
+		YearRange: app.Flag("year-range", "Range for year fields values, as min,max (YYYY). Default: 1901,2155").
+			String(),
+
+		Schema:    new(string),
+		TableName: new(string),
+		Rows:      new(int),
 	}
-	_, err := app.Parse(os.Args[1:])
+
+	tableCmd := app.Command("table", "Load random data into a table").Default()
+	tableCmd.Arg("database", "Database").Required().StringVar(opts.Schema)
+	tableCmd.Arg("table", "Table").Required().StringVar(opts.TableName)
+	tableCmd.Arg("rows", "Number of rows to insert").Required().IntVar(opts.Rows)
+
+	schemaCmd := app.Command("schema", "Load random data into all the tables of a database (or into a list of tables)"+
+		" in foreign keys dependency order")
+	schemaCmd.Arg("database", "Database").Required().StringVar(opts.Schema)
+	schemaCmd.Arg("rows", "Default number of rows to insert into each table").Required().IntVar(opts.Rows)
+	opts.Tables = schemaCmd.Arg("tables", "Tables to load. Default: all the tables in the database").Strings()
+	opts.TableRows = schemaCmd.Flag("table-rows", "Number of rows for a specific table, as table=rows."+
+		" Can be specified multiple times").StringMap()
+
+	generatorsCmd := app.Command("generators", "Print the generators used for the fields of the tables of a database"+
+		" (or of a list of tables), including the ones inferred from the fields names with --heuristics, as a"+
+		" generators file")
+	generatorsCmd.Arg("database", "Database").Required().StringVar(opts.Schema)
+	generatorsCmd.Arg("tables", "Tables. Default: all the tables in the database").StringsVar(opts.Tables)
+
+	profileCmd := app.Command("profile", "Write the distribution of the values of the tables of a database (or of a list"+
+		" of tables), to be used later with --profile-file")
+	profileCmd.Arg("database", "Database").Required().StringVar(opts.Schema)
+	profileCmd.Arg("tables", "Tables to profile. Default: all the tables in the database").StringsVar(opts.Tables)
+	opts.ProfileOutput = profileCmd.Flag("output", "Output file. Default: standard output").Short('o').String()
+	opts.SampleSize = profileCmd.Flag("sample-size", "Number of random rows read from each table. 0 to use only the"+
+		" histograms in information_schema.COLUMN_STATISTICS").Default("10000").Int64()
+
+	describeCmd := app.Command("describe", "Write the definition of the tables of a database (or of a list of tables) as JSON,"+
+		" to be used later with --table-definition")
+	describeCmd.Arg("database", "Database").Required().StringVar(opts.Schema)
+	describeCmd.Arg("tables", "Tables to describe. Default: all the tables in the database").StringsVar(opts.Tables)
+	opts.DefinitionOutput = describeCmd.Flag("output", "Output file. Default: standard output").Short('o').String()
+
+	cmd, err := app.Parse(os.Args[1:])
 
 	if err != nil {
 		return nil, err
 	}
+	opts.Command = cmd
 
 	if mysqlOpts, err := readMySQLConfigFile(*opts.ConfigFile); err == nil {
 		checkMySQLParams(opts, mysqlOpts)
@@ -1,21 +1,27 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
+	"math/rand"
 	"reflect"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/Percona-Lab/mysql_random_data_load/internal/generators"
 	"github.com/Percona-Lab/mysql_random_data_load/internal/getters"
 	"github.com/Percona-Lab/mysql_random_data_load/tableparser"
 	tu "github.com/Percona-Lab/mysql_random_data_load/testutils"
+	"github.com/gosuri/uiprogress"
 )
 
 func TestGetSamples(t *testing.T) {
 	conn := tu.GetMySQLConnection(t)
 	var wantRows int64 = 100
-	samples, err := getSamples(conn, "sakila", "inventory", "inventory_id", wantRows, "int")
+	samples, err := getSamples(conn, "sakila", "inventory", "inventory_id", wantRows, "int", 0)
 	tu.Ok(t, err, "error getting samples")
 	_, ok := samples[0].(int64)
 	tu.Assert(t, ok, "Wrong data type.")
@@ -23,13 +29,67 @@ func TestGetSamples(t *testing.T) {
 		"Wrong number of samples. Have %d, want 100.", len(samples))
 }
 
+func TestWriteRows(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	values := insertValues{
+		getters.NewConstant(int64(1)),
+		getters.NewConstant("a\tb"),
+		getters.NewConstant(nil),
+		getters.NewRandomString("f4", 10, false, rnd),
+	}
+	buf := &bytes.Buffer{}
+	bar := uiprogress.NewBar(3)
+	tu.Ok(t, writeRows(buf, 3, values, bar))
+	tu.Equals(t, 3, bar.Current())
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	tu.Equals(t, 3, len(lines))
+	for _, line := range lines {
+		fields := strings.Split(line, "\t")
+		tu.Equals(t, 4, len(fields))
+		tu.Equals(t, []string{"1", `a\tb`, `\N`}, fields[:3])
+	}
+}
+
+func TestLoadDataTable(t *testing.T) {
+	conn := tu.GetMySQLConnection(t)
+	_, err := conn.Exec("SET GLOBAL local_infile = 1")
+	tu.Ok(t, err)
+	_, err = conn.Exec("DROP TABLE IF EXISTS sakila.load_data_test")
+	tu.Ok(t, err)
+	_, err = conn.Exec("CREATE TABLE sakila.load_data_test (id INT PRIMARY KEY, name VARCHAR(10), created DATE NULL)")
+	tu.Ok(t, err)
+	defer conn.Exec("DROP TABLE sakila.load_data_test") // nolint: errcheck
+
+	table, err := tableparser.NewTable(conn, "sakila", "load_data_test")
+	tu.Ok(t, err)
+	maxRetries := 0
+	opts = &cliOptions{MaxRetries: &maxRetries}
+	defer func() { opts = nil }()
+
+	rnd := rand.New(rand.NewSource(1))
+	values := insertValues{
+		getters.NewSequence("id", 1),
+		getters.NewRandomString("name", 10, false, rnd),
+		getters.NewRandomDate("created", getters.DefaultTemporalRange("date"), true, rnd),
+	}
+	count, err := loadDataTable(conn, table, 500, values, uiprogress.NewBar(500))
+	tu.Ok(t, err)
+	tu.Equals(t, 500, count)
+
+	var rows int
+	tu.Ok(t, conn.QueryRow("SELECT COUNT(*) FROM sakila.load_data_test").Scan(&rows))
+	tu.Equals(t, 500, rows)
+}
+
 func TestGenerateInsertData(t *testing.T) {
 	wantRows := 3
+	rnd := rand.New(rand.NewSource(1))
 
 	values := []getter{
-		getters.NewRandomInt("f1", 100, false),
-		getters.NewRandomString("f2", 10, false),
-		getters.NewRandomDate("f3", false),
+		getters.NewRandomInt("f1", 100, false, rnd),
+		getters.NewRandomString("f2", 10, false, rnd),
+		getters.NewRandomDate("f3", getters.DefaultTemporalRange("date"), false, rnd),
 	}
 
 	rowsChan := make(chan []getter, 100)
@@ -79,3 +139,403 @@ func TestGenerateInsertStmt(t *testing.T) {
 	query := generateInsertStmt(table)
 	tu.Equals(t, want, query)
 }
+
+func TestSkipFields(t *testing.T) {
+	var table *tableparser.Table
+	tu.LoadJson(t, "sakila.film.json", &table)
+	fields := []tableparser.Field{}
+	for _, field := range table.Fields {
+		switch field.ColumnName {
+		case "length":
+			field.Extra = "VIRTUAL GENERATED"
+			field.GenerationExpression = "char_length(`title`)"
+		case "rating":
+			field.Extra = "INVISIBLE"
+		}
+		fields = append(fields, field)
+	}
+	table.Fields = fields
+
+	want := "INSERT IGNORE INTO `sakila`.`film` " +
+		"(`title`,`description`,`release_year`,`language_id`," +
+		"`original_language_id`,`rental_duration`,`rental_rate`," +
+		"`replacement_cost`,`special_features`," +
+		"`last_update`) VALUES "
+	tu.Equals(t, want, generateInsertStmt(table))
+
+	// rental_duration, rental_rate, replacement_cost, rating and last_update have default values
+	want = "INSERT IGNORE INTO `sakila`.`film` " +
+		"(`title`,`description`,`release_year`,`language_id`," +
+		"`original_language_id`,`special_features`) VALUES "
+	tu.Equals(t, want, generateInsertStmt(withoutDefaults(table)))
+	tu.Equals(t, len(fields), len(table.Fields))
+}
+
+// loadFilmFields returns the sakila.film table without the fields having foreign keys, so their values can be
+// generated without a connection, and the options used to generate them
+func loadFilmFields(t *testing.T) (*tableparser.Table, valueFuncsOptions) {
+	var table *tableparser.Table
+	tu.LoadJson(t, "sakila.film.json", &table)
+	fields := []tableparser.Field{}
+	for _, field := range table.Fields {
+		if field.Constraint == nil {
+			fields = append(fields, field)
+		}
+	}
+	table.Fields = fields
+	return table, valueFuncsOptions{seed: 1, maxBlobSize: 100}
+}
+
+func TestMakeUniqueKeys(t *testing.T) {
+	table, valueOpts := loadFilmFields(t)
+	// rental_duration is tinyint unsigned and rating has 5 values
+	table.Indexes["uk_duration_rating"] = tableparser.Index{Name: "uk_duration_rating", Unique: true,
+		Fields: []string{"rental_duration", "rating"}}
+
+	values, err := makeRowValues(nil, table, 1280, valueOpts)
+	tu.Ok(t, err)
+
+	names := getFieldNames(table.Fields)
+	seen := make(map[string]bool)
+	for i := 0; i < 1280; i++ {
+		var key string
+		for j, v := range values {
+			if q := v.Quote(); names[j] == "`rental_duration`" || names[j] == "`rating`" {
+				key += q + ","
+			}
+		}
+		tu.Assert(t, !seen[key], "Duplicated key %s", key)
+		seen[key] = true
+	}
+
+	_, err = makeRowValues(nil, table, 1281, valueOpts)
+	tu.NotOk(t, err)
+}
+
+func TestMakePrimaryKey(t *testing.T) {
+	table, valueOpts := loadFilmFields(t)
+	for i := range table.Fields {
+		if table.Fields[i].ColumnName == "film_id" {
+			table.Fields[i].Extra = ""
+		}
+	}
+	names := getFieldNames(table.Fields)
+	column := func(values insertValues, name string) getter {
+		for i, n := range names {
+			if n == "`"+name+"`" {
+				return values[i]
+			}
+		}
+		return nil
+	}
+
+	// film_id is a smallint unsigned primary key without auto_increment
+	values, err := makeRowValues(nil, table, 100, valueOpts)
+	tu.Ok(t, err)
+	for i := 1; i <= 3; i++ {
+		tu.Equals(t, strconv.Itoa(i), column(values, "film_id").Quote())
+	}
+	_, err = makeRowValues(nil, table, 70000, valueOpts)
+	tu.NotOk(t, err)
+
+	// Composite key having a counter per rating
+	table.Indexes["PRIMARY"] = tableparser.Index{Name: "PRIMARY", Unique: true, Fields: []string{"rating", "film_id"}}
+	values, err = makeRowValues(nil, table, 100, valueOpts)
+	tu.Ok(t, err)
+	counters := make(map[string]int)
+	for i := 0; i < 100; i++ {
+		rating := column(values, "rating").String()
+		counters[rating]++
+		tu.Equals(t, strconv.Itoa(counters[rating]), column(values, "film_id").String())
+	}
+
+	// varchar(128) primary key
+	table.Indexes["PRIMARY"] = tableparser.Index{Name: "PRIMARY", Unique: true, Fields: []string{"title"}}
+	values, err = makeRowValues(nil, table, 100, valueOpts)
+	tu.Ok(t, err)
+	tu.Equals(t, 36, len(column(values, "title").String()))
+}
+
+func TestApplyChecks(t *testing.T) {
+	table, valueOpts := loadFilmFields(t)
+	table.Checks = []tableparser.Check{
+		{Name: "c1", Clause: "(`rental_duration` between 3 and 5)", Enforced: true},
+		{Name: "c2", Clause: "((`rental_rate` > 0) and (`rental_rate` < 5))", Enforced: true},
+		{Name: "c3", Clause: "(`rating` in (_utf8mb4'G',_utf8mb4'PG',_utf8mb4'X'))", Enforced: true},
+		{Name: "c4", Clause: "(char_length(`title`) >= 20)", Enforced: true},
+		{Name: "c5", Clause: "(`replacement_cost` > `rental_rate`)", Enforced: true},
+		{Name: "c6", Clause: "((`length` > 0) or (`length` is null))", Enforced: true},
+		{Name: "c7", Clause: "(`rental_duration` > 100)", Enforced: false},
+	}
+
+	values, err := makeRowValues(nil, table, 1000, valueOpts)
+	tu.Ok(t, err)
+
+	names := getFieldNames(table.Fields)
+	for i := 0; i < 1000; i++ {
+		row := make(map[string]string)
+		for j, v := range values {
+			row[strings.Trim(names[j], "`")] = v.String()
+		}
+		duration, _ := strconv.Atoi(row["rental_duration"])
+		tu.Assert(t, duration >= 3 && duration <= 5, "rental_duration %d out of range", duration)
+		rate, _ := strconv.ParseFloat(row["rental_rate"], 64)
+		tu.Assert(t, rate > 0 && rate < 5, "rental_rate %s out of range", row["rental_rate"])
+		tu.Assert(t, row["rating"] == "G" || row["rating"] == "PG" || row["rating"] == "NULL", "invalid rating %s",
+			row["rating"])
+		tu.Assert(t, len(row["title"]) >= 20, "title %q is too short", row["title"])
+		cost, _ := strconv.ParseFloat(row["replacement_cost"], 64)
+		tu.Assert(t, cost > rate, "replacement_cost %v is not greater than rental_rate %v", cost, rate)
+		length, _ := strconv.Atoi(row["length"])
+		tu.Assert(t, length > 0 || row["length"] == "NULL", "length %s out of range", row["length"])
+	}
+
+	table.Checks = []tableparser.Check{{Name: "c1", Clause: "(`rental_duration` > 300)", Enforced: true}}
+	_, err = makeRowValues(nil, table, 1000, valueOpts)
+	tu.NotOk(t, err)
+}
+
+func TestMakeSpecGetter(t *testing.T) {
+	var table *tableparser.Table
+	tu.LoadJson(t, "sakila.film.json", &table)
+	fields := make(map[string]tableparser.Field)
+	for _, field := range table.Fields {
+		fields[field.ColumnName] = field
+	}
+	rnd := rand.New(rand.NewSource(1))
+	valueOpts := valueFuncsOptions{}
+
+	g, err := makeSpecGetter(nil, fields["rental_duration"], generators.Spec{Generator: "int", Min: "1", Max: "7"}, valueOpts, rnd)
+	tu.Ok(t, err)
+	for i := 0; i < 100; i++ {
+		v := g.Value().(uint64) // rental_duration is unsigned
+		tu.Assert(t, v >= 1 && v <= 7, "Invalid rental_duration %d", v)
+	}
+
+	// rental_duration is a tinyint unsigned and length a smallint unsigned
+	_, err = makeSpecGetter(nil, fields["rental_duration"], generators.Spec{Generator: "int", Min: "1", Max: "256"}, valueOpts, rnd)
+	tu.NotOk(t, err)
+	_, err = makeSpecGetter(nil, fields["length"], generators.Spec{Generator: "int", Min: "-1"}, valueOpts, rnd)
+	tu.NotOk(t, err)
+	_, err = makeSpecGetter(nil, fields["length"], generators.Spec{Generator: "int", Min: "10", Max: "5"}, valueOpts, rnd)
+	tu.NotOk(t, err)
+	signed := fields["length"]
+	signed.DataType, signed.ColumnType = "tinyint", "tinyint(4)"
+	_, err = makeSpecGetter(nil, signed, generators.Spec{Generator: "int", Min: "200"}, valueOpts, rnd)
+	tu.NotOk(t, err)
+	_, err = makeSpecGetter(nil, signed, generators.Spec{Generator: "int", Min: "-129", Max: "0"}, valueOpts, rnd)
+	tu.NotOk(t, err)
+	g, err = makeSpecGetter(nil, signed, generators.Spec{Generator: "int", Min: "-128", NullRatio: new(float64)}, valueOpts, rnd)
+	tu.Ok(t, err)
+	for i := 0; i < 100; i++ {
+		v := g.Value().(int64)
+		tu.Assert(t, v >= -128 && v <= 127, "Invalid tinyint %d", v)
+	}
+
+	g, err = makeSpecGetter(nil, fields["rating"], generators.Spec{Generator: "values", Values: []string{"G"}}, valueOpts, rnd)
+	tu.Ok(t, err)
+	tu.Equals(t, "G", g.Value())
+
+	ratio := 1.0
+	g, err = makeSpecGetter(nil, fields["description"], generators.Spec{Generator: "string", NullRatio: &ratio}, valueOpts, rnd)
+	tu.Ok(t, err)
+	tu.Equals(t, getters.NULL, g.Quote())
+
+	// title is NOT NULL
+	_, err = makeSpecGetter(nil, fields["title"], generators.Spec{Generator: "string", NullRatio: &ratio}, valueOpts, rnd)
+	tu.NotOk(t, err)
+
+	g, err = makeSpecGetter(nil, fields["description"], generators.Spec{Generator: "json",
+		Sample: []byte(`{"id": 1}`), NullRatio: new(float64)}, valueOpts, rnd)
+	tu.Ok(t, err)
+	tu.Assert(t, strings.HasPrefix(g.String(), `{"id":`), "Invalid JSON document %s", g.String())
+
+	// last_update is a timestamp field
+	g, err = makeSpecGetter(nil, fields["last_update"], generators.Spec{Generator: "date_in_range",
+		Min: "2019-01-01", Max: "2019-01-31 23:59:59"}, valueOpts, rnd)
+	tu.Ok(t, err)
+	for i := 0; i < 100; i++ {
+		v := g.String()
+		tu.Assert(t, v >= "2019-01-01 00:00:00" && v <= "2019-01-31 23:59:59", "Invalid last_update %s", v)
+	}
+
+	_, err = makeSpecGetter(nil, fields["title"], generators.Spec{Generator: "date_in_range"}, valueOpts, rnd)
+	tu.NotOk(t, err)
+
+	g, err = makeSpecGetter(nil, fields["special_features"], generators.Spec{Generator: "set", Min: "2", Max: "2",
+		NullRatio: new(float64)}, valueOpts, rnd)
+	tu.Ok(t, err)
+	for i := 0; i < 100; i++ {
+		v := g.Value().(string)
+		tu.Assert(t, strings.Count(v, ",") == 1, "Invalid special_features %q", v)
+	}
+
+	_, err = makeSpecGetter(nil, fields["special_features"], generators.Spec{Generator: "set", Max: "5"}, valueOpts, rnd)
+	tu.NotOk(t, err)
+
+	g, err = makeSpecGetter(nil, fields["special_features"], generators.Spec{Generator: "set", Min: "1",
+		Values: []string{"trailers", "Commentaries"}, NullRatio: new(float64)}, valueOpts, rnd)
+	tu.Ok(t, err)
+	for i := 0; i < 100; i++ {
+		v := g.Value().(string)
+		tu.Assert(t, v == "trailers" || v == "Commentaries" || v == "trailers,Commentaries", "Invalid special_features %q", v)
+	}
+	_, err = makeSpecGetter(nil, fields["special_features"], generators.Spec{Generator: "set",
+		Values: []string{"Trailers", "Bloopers"}}, valueOpts, rnd)
+	tu.NotOk(t, err)
+}
+
+func TestMakeSpecGetterDistributions(t *testing.T) {
+	var table *tableparser.Table
+	tu.LoadJson(t, "sakila.film.json", &table)
+	fields := make(map[string]tableparser.Field)
+	for _, field := range table.Fields {
+		fields[field.ColumnName] = field
+	}
+	rnd := rand.New(rand.NewSource(1))
+	valueOpts := valueFuncsOptions{}
+
+	g, err := makeSpecGetter(nil, fields["rental_duration"], generators.Spec{Generator: "int", Min: "1", Max: "100",
+		Distribution: &generators.Distribution{Type: "zipf", S: 2}}, valueOpts, rnd)
+	tu.Ok(t, err)
+	counts := make(map[uint64]int)
+	for i := 0; i < 1000; i++ {
+		v := g.Value().(uint64)
+		tu.Assert(t, v >= 1 && v <= 100, "Invalid rental_duration %d", v)
+		counts[v]++
+	}
+	tu.Assert(t, counts[1] > 500 && counts[1] > counts[2], "Invalid frequency %d for the hot key", counts[1])
+
+	g, err = makeSpecGetter(nil, fields["length"], generators.Spec{Generator: "int", Min: "0", Max: "200", NullRatio: new(float64),
+		Distribution: &generators.Distribution{Type: "normal", Mean: "120", StdDev: "10"}}, valueOpts, rnd)
+	tu.Ok(t, err)
+	sum := 0.0
+	for i := 0; i < 1000; i++ {
+		sum += float64(g.Value().(uint64))
+	}
+	tu.Assert(t, sum/1000 > 118 && sum/1000 < 122, "Invalid mean %v", sum/1000)
+
+	// Most of the last_update values are in the last days of January
+	g, err = makeSpecGetter(nil, fields["last_update"], generators.Spec{Generator: "date_in_range",
+		Min: "2019-01-01", Max: "2019-01-31 23:59:59", NullRatio: new(float64),
+		Distribution: &generators.Distribution{Type: "exponential", Rate: 10, Reverse: true}}, valueOpts, rnd)
+	tu.Ok(t, err)
+	recent := 0
+	for i := 0; i < 1000; i++ {
+		v := g.String()
+		tu.Assert(t, v >= "2019-01-01 00:00:00" && v <= "2019-01-31 23:59:59", "Invalid last_update %s", v)
+		if v >= "2019-01-25" {
+			recent++
+		}
+	}
+	tu.Assert(t, recent > 800, "Invalid number of recent values %d", recent)
+
+	g, err = makeSpecGetter(nil, fields["last_update"], generators.Spec{Generator: "date_in_range",
+		Min: "2019-01-01", Max: "2019-12-31", NullRatio: new(float64),
+		Distribution: &generators.Distribution{Type: "normal", Mean: "2019-07-01", StdDev: "1h"}}, valueOpts, rnd)
+	tu.Ok(t, err)
+	tu.Assert(t, strings.HasPrefix(g.String(), "2019-0"), "Invalid last_update %s", g.String())
+	_, err = makeSpecGetter(nil, fields["last_update"], generators.Spec{Generator: "date_in_range",
+		Distribution: &generators.Distribution{Type: "normal", StdDev: "10"}}, valueOpts, rnd)
+	tu.NotOk(t, err)
+
+	// Weights for the members of the rating enum
+	g, err = makeSpecGetter(nil, fields["rating"], generators.Spec{Generator: "values", NullRatio: new(float64),
+		Distribution: &generators.Distribution{Type: "weighted", Weights: []float64{0, 0, 1}}}, valueOpts, rnd)
+	tu.Ok(t, err)
+	tu.Equals(t, fields["rating"].SetEnumVals[2], g.Value())
+	_, err = makeSpecGetter(nil, fields["rating"], generators.Spec{Generator: "values",
+		Distribution: &generators.Distribution{Type: "weighted", Weights: make([]float64, 10)}}, valueOpts, rnd)
+	tu.NotOk(t, err)
+	_, err = makeSpecGetter(nil, fields["title"], generators.Spec{Generator: "values"}, valueOpts, rnd)
+	tu.NotOk(t, err)
+
+	// The samples generator needs a foreign key and a connection
+	_, err = makeSpecGetter(nil, fields["title"], generators.Spec{Generator: "samples"}, valueOpts, rnd)
+	tu.NotOk(t, err)
+	_, err = makeSpecGetter(nil, fields["language_id"], generators.Spec{Generator: "samples"}, valueOpts, rnd)
+	tu.NotOk(t, err)
+}
+
+func TestEmptySamplesGetter(t *testing.T) {
+	var table *tableparser.Table
+	tu.LoadJson(t, "sakila.film.json", &table)
+	fields := make(map[string]tableparser.Field)
+	for _, field := range table.Fields {
+		fields[field.ColumnName] = field
+	}
+	language := &tableparser.Table{Schema: "sakila", Name: "language", Constraints: []tableparser.Constraint{
+		{ReferencedTableSchema: "sakila", ReferencedTableName: "film"}}}
+	valueOpts := valueFuncsOptions{tables: []*tableparser.Table{table, language}}
+
+	// original_language_id is nullable
+	g, err := emptySamplesGetter(fields["original_language_id"], valueOpts)
+	tu.Ok(t, err)
+	tu.Equals(t, getters.NULL, g.Quote())
+
+	_, err = emptySamplesGetter(fields["language_id"], valueOpts)
+	tu.NotOk(t, err)
+	tu.Assert(t, strings.Contains(err.Error(), "film -> language -> film"), "The error must name the cycle: %s", err)
+
+	valueOpts.tables = []*tableparser.Table{table}
+	_, err = emptySamplesGetter(fields["language_id"], valueOpts)
+	tu.NotOk(t, err)
+}
+
+func TestFieldSpec(t *testing.T) {
+	var table *tableparser.Table
+	tu.LoadJson(t, "sakila.film.json", &table)
+	fields := make(map[string]tableparser.Field)
+	for _, field := range table.Fields {
+		fields[field.ColumnName] = field
+	}
+	specs := generators.Specs{"sakila.film.title": {Generator: "string", Length: 10}}
+	valueOpts := valueFuncsOptions{specs: specs, heuristics: true}
+
+	spec, ok := fieldSpec(fields["title"], valueOpts)
+	tu.Assert(t, ok, "Missing spec for title")
+	tu.Equals(t, generators.Spec{Generator: "string", Length: 10}, spec)
+
+	spec, ok = fieldSpec(fields["last_update"], valueOpts)
+	tu.Assert(t, ok, "Missing inferred spec for last_update")
+	tu.Equals(t, "date_in_range", spec.Generator)
+
+	// Fields having foreign keys keep their samples
+	_, ok = fieldSpec(fields["language_id"], valueOpts)
+	tu.Assert(t, !ok, "Unexpected spec for language_id")
+
+	valueOpts.heuristics = false
+	_, ok = fieldSpec(fields["last_update"], valueOpts)
+	tu.Assert(t, !ok, "Unexpected spec for last_update without heuristics")
+
+	rnd := rand.New(rand.NewSource(1))
+	g, err := makeSpecGetter(nil, fields["description"], generators.Spec{Generator: "fake", Kind: "email",
+		NullRatio: new(float64)}, valueOpts, rnd)
+	tu.Ok(t, err)
+	tu.Assert(t, strings.Contains(g.String(), "@"), "Invalid email")
+	_, err = makeSpecGetter(nil, fields["length"], generators.Spec{Generator: "fake", Kind: "email"}, valueOpts, rnd)
+	tu.NotOk(t, err)
+}
+
+func TestSeed(t *testing.T) {
+	table, valueOpts := loadFilmFields(t)
+	valueOpts.samples = 100
+
+	generate := func(seed int64) []string {
+		valueOpts.seed = seed
+		values, err := makeValueFuncs(nil, table.Fields, valueOpts)
+		tu.Ok(t, err)
+		rows := []string{}
+		for i := 0; i < 10; i++ {
+			for _, v := range values {
+				rows = append(rows, v.Quote())
+			}
+		}
+		return rows
+	}
+
+	getters.SetReferenceTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer getters.SetReferenceTime(time.Now())
+	tu.Equals(t, generate(42), generate(42))
+	tu.Assert(t, !reflect.DeepEqual(generate(42), generate(43)), "Different seeds generated the same values")
+}
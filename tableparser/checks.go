@@ -0,0 +1,480 @@
+package tableparser
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// CheckCondition is a condition on a single field found in a CHECK constraint clause, like `price` > 0
+type CheckCondition struct {
+	Column string
+	// Function is "length" or "char_length" if the condition is on the length of the field value,
+	// like CHAR_LENGTH(`name`) >= 3
+	Function string
+	// Op is one of =, <>, <, <=, >, >=, between, in, not in, is null and is not null
+	Op string
+	// Values are the literal values: one value for comparisons, two for between and the list for in
+	Values []string
+	// RefColumn is the other field for comparisons between fields, like `end_date` > `start_date`
+	RefColumn string
+}
+
+// ParseCheck parses a CHECK constraint clause into the conditions on single fields it is made of.
+// Only conjunctions (AND) of comparisons, BETWEEN, IN, IS NULL and length conditions are supported,
+// and disjunctions (OR) allowing a field to be NULL, like (`price` > 0) OR (`price` IS NULL); it returns
+// an error for any other expression.
+func ParseCheck(clause string) ([]CheckCondition, error) {
+	tokens, err := tokenizeCheck(clause)
+	if err != nil {
+		return nil, err
+	}
+	p := &checkParser{tokens: tokens}
+	conditions, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unsupported expression near %q", tok.text)
+	}
+	return conditions, nil
+}
+
+const (
+	tokEOF = iota
+	tokWord
+	tokColumn
+	tokNumber
+	tokString
+	tokSymbol
+)
+
+type checkToken struct {
+	kind int
+	text string
+	// start and end are the offsets of the token in the tokenized text
+	start int
+	end   int
+}
+
+// checkSymbols are the operators and punctuation in CHECK clauses and SQL statements. Longer symbols go first
+var checkSymbols = []string{"<=>", "->>", "<>", "!=", "<=", ">=", "&&", "||", "<<", ">>", ":=", "->", "=", "<", ">", "(", ")",
+	",", "-", "+", "!", ".", ";", "*", "/", "%", "&", "|", "^", "~", ":", "@", "?", "{", "}", "[", "]"}
+
+// tokenizeCheck splits a CHECK clause or an SQL statement into tokens. Charset introducers, like in
+// _utf8mb4'abc', are removed. Hex and bit literals, like x'0f' and b'101', are strings having their bytes
+func tokenizeCheck(clause string) ([]checkToken, error) {
+	tokens := []checkToken{}
+	// literal is the hex or bit literal prefix before a string, starting at literalStart
+	literal, literalStart := "", 0
+	for i := 0; i < len(clause); {
+		c := clause[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '`':
+			var name strings.Builder
+			for i++; i < len(clause); i++ {
+				if clause[i] == '`' {
+					if i+1 < len(clause) && clause[i+1] == '`' {
+						i++
+					} else {
+						break
+					}
+				}
+				name.WriteByte(clause[i])
+			}
+			if i >= len(clause) {
+				return nil, fmt.Errorf("unterminated identifier in %q", clause)
+			}
+			i++
+			tokens = append(tokens, checkToken{tokColumn, name.String(), start, i})
+		case c == '\'' || c == '"':
+			var val strings.Builder
+			for i++; i < len(clause); i++ {
+				if clause[i] == '\\' && i+1 < len(clause) {
+					i++
+				} else if clause[i] == c {
+					if i+1 < len(clause) && clause[i+1] == c {
+						i++
+					} else {
+						break
+					}
+				}
+				val.WriteByte(clause[i])
+			}
+			if i >= len(clause) {
+				return nil, fmt.Errorf("unterminated string in %q", clause)
+			}
+			i++
+			text := val.String()
+			if literal != "" {
+				var err error
+				if text, err = literalBytes(literal, text); err != nil {
+					return nil, err
+				}
+				start, literal = literalStart, ""
+			}
+			tokens = append(tokens, checkToken{tokString, text, start, i})
+		case c >= '0' && c <= '9' || c == '.' && i+1 < len(clause) && clause[i+1] >= '0' && clause[i+1] <= '9':
+			for i < len(clause) && strings.IndexByte("0123456789.eE", clause[i]) >= 0 {
+				// Exponent sign, like in 1e-5
+				if (clause[i] == 'e' || clause[i] == 'E') && i+1 < len(clause) && strings.IndexByte("+-", clause[i+1]) >= 0 {
+					i++
+				}
+				i++
+			}
+			tokens = append(tokens, checkToken{tokNumber, clause[start:i], start, i})
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			for i < len(clause) && (clause[i] == '_' || clause[i] == '$' || clause[i] >= 'a' && clause[i] <= 'z' ||
+				clause[i] >= 'A' && clause[i] <= 'Z' || clause[i] >= '0' && clause[i] <= '9') {
+				i++
+			}
+			// Charset introducer or hex/bit literal prefix
+			word := strings.ToLower(clause[start:i])
+			if i < len(clause) && clause[i] == '\'' && (c == '_' || word == "n") {
+				continue
+			}
+			if i < len(clause) && clause[i] == '\'' && (word == "x" || word == "b") {
+				literal, literalStart = word, start
+				continue
+			}
+			tokens = append(tokens, checkToken{tokWord, word, start, i})
+		default:
+			symbol := ""
+			for _, s := range checkSymbols {
+				if strings.HasPrefix(clause[i:], s) {
+					symbol = s
+					break
+				}
+			}
+			if symbol == "" {
+				return nil, fmt.Errorf("unexpected char %q in %q", c, clause)
+			}
+			tokens = append(tokens, checkToken{tokSymbol, symbol, start, i + len(symbol)})
+			i += len(symbol)
+		}
+	}
+	return tokens, nil
+}
+
+// literalBytes returns the bytes of a hex (x'0f') or bit (b'101') literal as a string
+func literalBytes(prefix, digits string) (string, error) {
+	if prefix == "x" {
+		b, err := hex.DecodeString(digits)
+		if err != nil {
+			return "", fmt.Errorf("invalid hex literal x'%s'", digits)
+		}
+		return string(b), nil
+	}
+	b := make([]byte, (len(digits)+7)/8)
+	for i, d := range digits {
+		if d != '0' && d != '1' {
+			return "", fmt.Errorf("invalid bit literal b'%s'", digits)
+		}
+		// The last digit is the lowest bit of the last byte
+		bit := len(digits) - 1 - i
+		b[len(b)-1-bit/8] |= byte(d-'0') << uint(bit%8)
+	}
+	return string(b), nil
+}
+
+type checkParser struct {
+	tokens []checkToken
+	pos    int
+}
+
+func (p *checkParser) peek() checkToken {
+	if p.pos >= len(p.tokens) {
+		return checkToken{kind: tokEOF}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *checkParser) next() checkToken {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+func (p *checkParser) accept(kind int, text string) bool {
+	if tok := p.peek(); tok.kind == kind && tok.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *checkParser) expect(kind int, text string) error {
+	if !p.accept(kind, text) {
+		return fmt.Errorf("expected %q near %q", text, p.peek().text)
+	}
+	return nil
+}
+
+// parseOr parses a disjunction. NULL values satisfy CHECK constraints, so conditions on a field OR the
+// field IS NULL are the same as the conditions on the field
+func (p *checkParser) parseOr() ([]CheckCondition, error) {
+	conditions, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokWord, "or") || p.accept(tokSymbol, "||") {
+		more, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if conditions, err = orNull(conditions, more); err != nil {
+			return nil, err
+		}
+	}
+	return conditions, nil
+}
+
+// orNull returns the conditions for a OR b, where one of them is a single IS NULL condition and the other
+// one only has conditions on the same field. IS NOT NULL conditions are removed since the field can be NULL.
+func orNull(a, b []CheckCondition) ([]CheckCondition, error) {
+	if len(b) == 1 && b[0].Op == "is null" {
+		a, b = b, a
+	}
+	if len(a) != 1 || a[0].Op != "is null" {
+		return nil, fmt.Errorf("OR expressions are only supported to allow NULL values")
+	}
+	conditions := []CheckCondition{}
+	for _, c := range b {
+		if c.Column != a[0].Column {
+			return nil, fmt.Errorf("OR expressions are only supported to allow NULL values")
+		}
+		if c.Op != "is not null" {
+			conditions = append(conditions, c)
+		}
+	}
+	return conditions, nil
+}
+
+func (p *checkParser) parseAnd() ([]CheckCondition, error) {
+	conditions, err := p.parsePredicate()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokWord, "and") || p.accept(tokSymbol, "&&") {
+		more, err := p.parsePredicate()
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, more...)
+	}
+	if tok := p.peek(); tok.text == "xor" {
+		return nil, fmt.Errorf("XOR expressions are not supported")
+	}
+	return conditions, nil
+}
+
+// checkOperand is a field, the length of a field or a literal value
+type checkOperand struct {
+	column   string
+	function string
+	literal  string
+	isValue  bool
+}
+
+func (p *checkParser) parsePredicate() ([]CheckCondition, error) {
+	if p.accept(tokSymbol, "(") {
+		conditions, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return conditions, p.expect(tokSymbol, ")")
+	}
+	if tok := p.peek(); tok.text == "not" || tok.text == "!" {
+		return nil, fmt.Errorf("NOT expressions are not supported")
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	tok := p.next()
+	switch {
+	case tok.kind == tokWord && tok.text == "is":
+		op := "is null"
+		if p.accept(tokWord, "not") {
+			op = "is not null"
+		}
+		if err := p.expect(tokWord, "null"); err != nil {
+			return nil, err
+		}
+		if left.isValue || left.function != "" {
+			return nil, fmt.Errorf("unsupported IS NULL expression")
+		}
+		return []CheckCondition{{Column: left.column, Op: op}}, nil
+	case tok.kind == tokWord && (tok.text == "in" || tok.text == "not"):
+		op := "in"
+		if tok.text == "not" {
+			if !p.accept(tokWord, "in") {
+				return nil, fmt.Errorf("NOT %s expressions are not supported", strings.ToUpper(p.peek().text))
+			}
+			op = "not in"
+		}
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		if left.isValue {
+			return nil, fmt.Errorf("unsupported IN expression")
+		}
+		return []CheckCondition{{Column: left.column, Function: left.function, Op: op, Values: values}}, nil
+	case tok.kind == tokWord && tok.text == "between":
+		min, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokWord, "and"); err != nil {
+			return nil, err
+		}
+		max, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		if left.isValue {
+			return nil, fmt.Errorf("unsupported BETWEEN expression")
+		}
+		return []CheckCondition{{Column: left.column, Function: left.function, Op: "between",
+			Values: []string{min, max}}}, nil
+	case tok.kind == tokSymbol && isComparison(tok.text):
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return comparison(tok.text, left, right)
+	}
+	return nil, fmt.Errorf("unsupported expression near %q", tok.text)
+}
+
+func (p *checkParser) parseOperand() (checkOperand, error) {
+	tok := p.peek()
+	switch {
+	case tok.kind == tokColumn:
+		p.next()
+		return checkOperand{column: tok.text}, nil
+	case tok.kind == tokWord && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].text == "(":
+		p.pos += 2
+		function := tok.text
+		switch function {
+		case "length", "octet_length":
+			function = "length"
+		case "char_length", "character_length":
+			function = "char_length"
+		default:
+			return checkOperand{}, fmt.Errorf("function %s is not supported", strings.ToUpper(tok.text))
+		}
+		arg := p.next()
+		if arg.kind != tokColumn && arg.kind != tokWord {
+			return checkOperand{}, fmt.Errorf("unsupported %s argument %q", strings.ToUpper(tok.text), arg.text)
+		}
+		return checkOperand{column: arg.text, function: function}, p.expect(tokSymbol, ")")
+	case tok.kind == tokWord && tok.text != "null" && tok.text != "true" && tok.text != "false":
+		// Unquoted field name
+		p.next()
+		return checkOperand{column: tok.text}, nil
+	}
+	literal, err := p.parseLiteral()
+	return checkOperand{literal: literal, isValue: true}, err
+}
+
+// parseLiteral parses a number or a string. Negative numbers can be written as -(5), like in the
+// clauses in INFORMATION_SCHEMA
+func (p *checkParser) parseLiteral() (string, error) {
+	tok := p.next()
+	switch {
+	case tok.kind == tokNumber || tok.kind == tokString:
+		return tok.text, nil
+	case tok.kind == tokWord && tok.text == "true":
+		return "1", nil
+	case tok.kind == tokWord && tok.text == "false":
+		return "0", nil
+	case tok.kind == tokSymbol && (tok.text == "-" || tok.text == "+"):
+		parens := p.accept(tokSymbol, "(")
+		v, err := p.parseLiteral()
+		if err != nil {
+			return "", err
+		}
+		if parens {
+			if err := p.expect(tokSymbol, ")"); err != nil {
+				return "", err
+			}
+		}
+		if tok.text == "+" {
+			return v, nil
+		}
+		if strings.HasPrefix(v, "-") {
+			return v[1:], nil
+		}
+		return "-" + v, nil
+	}
+	return "", fmt.Errorf("expected a value near %q", tok.text)
+}
+
+func (p *checkParser) parseList() ([]string, error) {
+	if err := p.expect(tokSymbol, "("); err != nil {
+		return nil, err
+	}
+	values := []string{}
+	for {
+		v, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		if !p.accept(tokSymbol, ",") {
+			break
+		}
+	}
+	return values, p.expect(tokSymbol, ")")
+}
+
+func isComparison(op string) bool {
+	switch op {
+	case "=", "<>", "!=", "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
+
+// comparison returns the condition for left op right, having the field on the left side
+func comparison(op string, left, right checkOperand) ([]CheckCondition, error) {
+	if op == "!=" {
+		op = "<>"
+	}
+	switch {
+	case !left.isValue && right.isValue:
+		return []CheckCondition{{Column: left.column, Function: left.function, Op: op, Values: []string{right.literal}}}, nil
+	case left.isValue && !right.isValue:
+		return comparison(reverseComparison(op), right, left)
+	case !left.isValue && !right.isValue && left.function == "" && right.function == "":
+		return []CheckCondition{{Column: left.column, Op: op, RefColumn: right.column}}, nil
+	}
+	return nil, fmt.Errorf("unsupported comparison %s", op)
+}
+
+// reverseComparison returns the operator to use swapping the operands, like a < b -> b > a
+func reverseComparison(op string) string {
+	switch op {
+	case "<":
+		return ">"
+	case "<=":
+		return ">="
+	case ">":
+		return "<"
+	case ">=":
+		return "<="
+	}
+	return op
+}
+
+// Reverse returns the same comparison between fields having the fields swapped, like `a` < `b` -> `b` > `a`
+func (c CheckCondition) Reverse() CheckCondition {
+	c.Column, c.RefColumn, c.Op = c.RefColumn, c.Column, reverseComparison(c.Op)
+	return c
+}
@@ -0,0 +1,1021 @@
+package tableparser
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// ParseDDL builds the tables defined by the CREATE TABLE statements in a file, like the ones written by
+// mysqldump --no-data, without a connection to the server. Tables are created in schema unless their names are
+// qualified or there is a USE statement before them. Triggers defined in the file are added to their tables.
+// Other statements are ignored.
+func ParseDDL(r io.Reader, schema string) ([]*Table, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := []*Table{}
+	triggers := []Trigger{}
+	for _, stmt := range splitStatements(string(data)) {
+		tokens, err := tokenizeCheck(stmt)
+		if err != nil {
+			return nil, err
+		}
+		p := &ddlParser{stmt: stmt, tokens: tokens}
+		switch {
+		case p.accept(tokWord, "use"):
+			if tok := p.next(); tok.kind == tokColumn || tok.kind == tokWord {
+				schema = tok.text
+			}
+		case p.isCreate("table"):
+			table, err := p.parseCreateTable(schema)
+			if err != nil {
+				return nil, err
+			}
+			tables = append(tables, table)
+		case p.isCreate("trigger"):
+			if trigger, ok := p.parseCreateTrigger(); ok {
+				triggers = append(triggers, trigger)
+			}
+		}
+	}
+
+	for _, trigger := range triggers {
+		for _, table := range tables {
+			if table.Name == trigger.Table {
+				table.Triggers = append(table.Triggers, trigger)
+			}
+		}
+	}
+	return tables, nil
+}
+
+// ParseCreateTable builds a table from a CREATE TABLE statement. The table is created in schema unless
+// its name is qualified.
+func ParseCreateTable(schema, statement string) (*Table, error) {
+	tables, err := ParseDDL(strings.NewReader(statement), schema)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) != 1 {
+		return nil, fmt.Errorf("expected a CREATE TABLE statement")
+	}
+	return tables[0], nil
+}
+
+// splitStatements splits SQL text into statements, removing comments. The content of conditional comments
+// like /*!40101 ... */ is kept and DELIMITER commands, used by mysqldump around triggers, are supported.
+func splitStatements(text string) []string {
+	statements := []string{}
+	delimiter := ";"
+	conditional := false
+	var stmt strings.Builder
+
+	addStatement := func() {
+		if s := strings.TrimSpace(stmt.String()); s != "" {
+			statements = append(statements, s)
+		}
+		stmt.Reset()
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		atLineStart := strings.TrimSpace(stmt.String()) == "" && (i == 0 || text[i-1] == '\n')
+		switch {
+		case atLineStart && len(text) >= i+10 && strings.EqualFold(text[i:i+10], "DELIMITER "):
+			end := strings.IndexByte(text[i:], '\n')
+			if end < 0 {
+				end = len(text) - i
+			}
+			delimiter = strings.TrimSpace(text[i+10 : i+end])
+			i += end
+		case c == '\'' || c == '"' || c == '`':
+			j := i + 1
+			for j < len(text) && text[j] != c {
+				if text[j] == '\\' && c != '`' {
+					j++
+				}
+				j++
+			}
+			if j < len(text) {
+				j++
+			}
+			stmt.WriteString(text[i:j])
+			i = j
+		case strings.HasPrefix(text[i:], "/*!"):
+			// Conditional comment: the version number is skipped and the content is kept
+			conditional = true
+			i += 3
+			for i < len(text) && text[i] >= '0' && text[i] <= '9' {
+				i++
+			}
+			stmt.WriteByte(' ')
+		case conditional && strings.HasPrefix(text[i:], "*/"):
+			conditional = false
+			i += 2
+			stmt.WriteByte(' ')
+		case strings.HasPrefix(text[i:], "/*"):
+			end := strings.Index(text[i+2:], "*/")
+			if end < 0 {
+				end = len(text) - i - 4
+			}
+			i += end + 4
+			stmt.WriteByte(' ')
+		case strings.HasPrefix(text[i:], "-- ") || strings.HasPrefix(text[i:], "--\n") || c == '#':
+			end := strings.IndexByte(text[i:], '\n')
+			if end < 0 {
+				end = len(text) - i
+			}
+			i += end
+		case strings.HasPrefix(text[i:], delimiter):
+			addStatement()
+			i += len(delimiter)
+		default:
+			stmt.WriteByte(c)
+			i++
+		}
+	}
+	addStatement()
+	return statements
+}
+
+// ddlParser parses CREATE TABLE and CREATE TRIGGER statements
+type ddlParser struct {
+	stmt   string
+	tokens []checkToken
+	pos    int
+}
+
+func (p *ddlParser) peek() checkToken {
+	return p.peekAt(0)
+}
+
+func (p *ddlParser) peekAt(n int) checkToken {
+	if p.pos+n >= len(p.tokens) {
+		return checkToken{kind: tokEOF, start: len(p.stmt), end: len(p.stmt)}
+	}
+	return p.tokens[p.pos+n]
+}
+
+func (p *ddlParser) next() checkToken {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+func (p *ddlParser) accept(kind int, text string) bool {
+	if tok := p.peek(); tok.kind == kind && tok.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// acceptWords accepts a sequence of words, like NOT NULL
+func (p *ddlParser) acceptWords(words ...string) bool {
+	for i, w := range words {
+		if tok := p.peekAt(i); tok.kind != tokWord || tok.text != w {
+			return false
+		}
+	}
+	p.pos += len(words)
+	return true
+}
+
+func (p *ddlParser) expect(kind int, text string) error {
+	if !p.accept(kind, text) {
+		return fmt.Errorf("expected %q near %q", text, p.context())
+	}
+	return nil
+}
+
+// context returns the text around the current token for error messages
+func (p *ddlParser) context() string {
+	start := p.peek().start
+	end := start + 30
+	if end > len(p.stmt) {
+		end = len(p.stmt)
+	}
+	return p.stmt[start:end]
+}
+
+// isCreate returns true if the statement is a CREATE statement for the object type, like CREATE TABLE or
+// CREATE DEFINER=`root`@`localhost` TRIGGER
+func (p *ddlParser) isCreate(object string) bool {
+	if tok := p.peek(); tok.kind != tokWord || tok.text != "create" {
+		return false
+	}
+	for i := 1; i < len(p.tokens) && i < 12; i++ {
+		tok := p.peekAt(i)
+		if tok.kind == tokWord && tok.text == object {
+			p.pos += i + 1
+			return true
+		}
+		if tok.kind == tokWord && (tok.text == "table" || tok.text == "trigger" || tok.text == "view" ||
+			tok.text == "procedure" || tok.text == "function" || tok.text == "database" || tok.text == "index") {
+			return false
+		}
+	}
+	return false
+}
+
+// name parses an identifier, quoted or not
+func (p *ddlParser) name() (string, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokColumn, tokString:
+		return tok.text, nil
+	case tokWord:
+		// Words are lowercased by the tokenizer
+		return p.stmt[tok.start:tok.end], nil
+	}
+	return "", fmt.Errorf("expected a name near %q", p.context())
+}
+
+// qualifiedName parses a name like `schema`.`table`. The schema is empty if the name is not qualified
+func (p *ddlParser) qualifiedName() (string, string, error) {
+	name, err := p.name()
+	if err != nil {
+		return "", "", err
+	}
+	if !p.accept(tokSymbol, ".") {
+		return "", name, nil
+	}
+	table, err := p.name()
+	return name, table, err
+}
+
+// skipParens skips a parenthesized expression and returns its text, without the parentheses
+func (p *ddlParser) skipParens() (string, error) {
+	open := p.peek()
+	if err := p.expect(tokSymbol, "("); err != nil {
+		return "", err
+	}
+	for depth := 1; depth > 0; {
+		tok := p.next()
+		switch {
+		case tok.kind == tokEOF:
+			return "", fmt.Errorf("unbalanced parentheses near %q", p.stmt[open.start:])
+		case tok.kind == tokSymbol && tok.text == "(":
+			depth++
+		case tok.kind == tokSymbol && tok.text == ")":
+			depth--
+			if depth == 0 {
+				return strings.TrimSpace(p.stmt[open.end:tok.start]), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// skipDefinition skips the tokens until the end of the current definition, at a comma or at the
+// closing parenthesis of the definitions list
+func (p *ddlParser) skipDefinition() {
+	for depth := 0; ; {
+		tok := p.peek()
+		switch {
+		case tok.kind == tokEOF:
+			return
+		case tok.kind == tokSymbol && tok.text == "(":
+			depth++
+		case tok.kind == tokSymbol && (tok.text == ")" || tok.text == ","):
+			if depth == 0 {
+				return
+			}
+			if tok.text == ")" {
+				depth--
+			}
+		}
+		p.next()
+	}
+}
+
+// tableDef holds the table definitions that are processed after all the columns have been parsed
+type tableDef struct {
+	table    *Table
+	charset  string
+	fields   map[string]*fieldDef
+	indexes  []Index
+	fkCount  int
+	chkCount int
+	// fkIndexes are the implicit indexes of the foreign keys
+	fkIndexes []Index
+}
+
+type fieldDef struct {
+	charset   string
+	collation string
+}
+
+func (p *ddlParser) parseCreateTable(schema string) (*Table, error) {
+	p.acceptWords("if", "not", "exists")
+	tableSchema, name, err := p.qualifiedName()
+	if err != nil {
+		return nil, err
+	}
+	if tableSchema == "" {
+		tableSchema = schema
+	}
+	if p.accept(tokWord, "like") {
+		return nil, fmt.Errorf("cannot parse table %s: CREATE TABLE ... LIKE is not supported", name)
+	}
+	def := &tableDef{
+		table:  &Table{Schema: tableSchema, Name: name, Indexes: make(map[string]Index)},
+		fields: make(map[string]*fieldDef),
+	}
+	if err := p.expect(tokSymbol, "("); err != nil {
+		return nil, fmt.Errorf("cannot parse table %s: %s", name, err)
+	}
+	for {
+		if err := p.parseDefinition(def); err != nil {
+			return nil, fmt.Errorf("cannot parse table %s: %s", name, err)
+		}
+		p.skipDefinition()
+		if p.accept(tokSymbol, ",") {
+			continue
+		}
+		if err := p.expect(tokSymbol, ")"); err != nil {
+			return nil, fmt.Errorf("cannot parse table %s: %s", name, err)
+		}
+		break
+	}
+	p.parseTableOptions(def)
+	def.finish()
+	return def.table, nil
+}
+
+func (p *ddlParser) parseTableOptions(def *tableDef) {
+	for tok := p.next(); tok.kind != tokEOF; tok = p.next() {
+		if tok.kind != tokWord {
+			continue
+		}
+		switch {
+		case tok.text == "charset" || tok.text == "character" && p.accept(tokWord, "set"):
+			p.accept(tokSymbol, "=")
+			def.charset = p.next().text
+		case tok.text == "partition":
+			return
+		}
+	}
+}
+
+func (p *ddlParser) parseDefinition(def *tableDef) error {
+	constraintName := ""
+	if p.accept(tokWord, "constraint") {
+		if tok := p.peek(); tok.kind == tokColumn || tok.kind == tokWord && tok.text != "primary" &&
+			tok.text != "unique" && tok.text != "foreign" && tok.text != "check" {
+			constraintName = p.next().text
+		}
+	}
+
+	tok := p.peek()
+	if tok.kind == tokWord {
+		switch tok.text {
+		case "primary":
+			p.acceptWords("primary", "key")
+			return p.parseIndex(def, "PRIMARY", true)
+		case "unique":
+			p.next()
+			if !p.accept(tokWord, "key") {
+				p.accept(tokWord, "index")
+			}
+			return p.parseIndex(def, constraintName, true)
+		case "key", "index":
+			p.next()
+			return p.parseIndex(def, "", false)
+		case "fulltext", "spatial":
+			p.next()
+			if !p.accept(tokWord, "key") {
+				p.accept(tokWord, "index")
+			}
+			return p.parseIndex(def, "", false)
+		case "foreign":
+			p.acceptWords("foreign", "key")
+			return p.parseForeignKey(def, constraintName)
+		case "check":
+			p.next()
+			return p.parseCheck(def, constraintName)
+		}
+	}
+	return p.parseColumn(def)
+}
+
+// parseIndex parses an index definition after the index type keywords
+func (p *ddlParser) parseIndex(def *tableDef, name string, unique bool) error {
+	if tok := p.peek(); tok.kind == tokColumn || tok.kind == tokWord && tok.text != "using" {
+		indexName, err := p.name()
+		if err != nil {
+			return err
+		}
+		if name != "PRIMARY" {
+			name = indexName
+		}
+	}
+	if p.accept(tokWord, "using") {
+		p.next()
+	}
+	if err := p.expect(tokSymbol, "("); err != nil {
+		return err
+	}
+	index := Index{Name: name, Unique: unique, Visible: true}
+	for {
+		if p.peek().kind == tokSymbol && p.peek().text == "(" {
+			// Functional key part
+			expr, err := p.skipParens()
+			if err != nil {
+				return err
+			}
+			index.Expression = expr
+		} else {
+			column, err := p.name()
+			if err != nil {
+				return err
+			}
+			index.Fields = append(index.Fields, column)
+			// Prefix length
+			if p.peek().text == "(" {
+				length, err := p.skipParens()
+				if err != nil {
+					return err
+				}
+				if n, err := strconv.ParseInt(length, 10, 64); err == nil {
+					if index.SubParts == nil {
+						index.SubParts = make(map[string]int64)
+					}
+					index.SubParts[column] = n
+				}
+			}
+		}
+		p.accept(tokWord, "asc")
+		p.accept(tokWord, "desc")
+		if !p.accept(tokSymbol, ",") {
+			break
+		}
+	}
+	if err := p.expect(tokSymbol, ")"); err != nil {
+		return err
+	}
+	for tok := p.peek(); tok.kind == tokWord; tok = p.peek() {
+		p.next()
+		if tok.text == "invisible" {
+			index.Visible = false
+		}
+	}
+	def.indexes = append(def.indexes, index)
+	return nil
+}
+
+func (p *ddlParser) parseForeignKey(def *tableDef, name string) error {
+	indexName := ""
+	if tok := p.peek(); tok.kind == tokColumn || tok.kind == tokWord {
+		var err error
+		if indexName, err = p.name(); err != nil {
+			return err
+		}
+	}
+	columns, err := p.nameList()
+	if err != nil {
+		return err
+	}
+	if err := p.expect(tokWord, "references"); err != nil {
+		return err
+	}
+	refSchema, refTable, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	if refSchema == "" {
+		refSchema = def.table.Schema
+	}
+	refColumns, err := p.nameList()
+	if err != nil {
+		return err
+	}
+	if len(columns) != len(refColumns) {
+		return fmt.Errorf("the foreign key has %d columns but references %d columns", len(columns), len(refColumns))
+	}
+	def.fkCount++
+	if name == "" {
+		name = fmt.Sprintf("%s_ibfk_%d", def.table.Name, def.fkCount)
+	}
+	if indexName == "" {
+		indexName = name
+	}
+	def.fkIndexes = append(def.fkIndexes, Index{Name: indexName, Fields: columns, Visible: true})
+	for i, column := range columns {
+		def.table.Constraints = append(def.table.Constraints, Constraint{
+			ConstraintName:        name,
+			ColumnName:            column,
+			ReferencedTableSchema: refSchema,
+			ReferencedTableName:   refTable,
+			ReferencedColumnName:  refColumns[i],
+		})
+	}
+	return nil
+}
+
+func (p *ddlParser) nameList() ([]string, error) {
+	if err := p.expect(tokSymbol, "("); err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !p.accept(tokSymbol, ",") {
+			break
+		}
+	}
+	return names, p.expect(tokSymbol, ")")
+}
+
+func (p *ddlParser) parseCheck(def *tableDef, name string) error {
+	clause, err := p.skipParens()
+	if err != nil {
+		return err
+	}
+	def.chkCount++
+	if name == "" {
+		name = fmt.Sprintf("%s_chk_%d", def.table.Name, def.chkCount)
+	}
+	enforced := !p.acceptWords("not", "enforced")
+	p.accept(tokWord, "enforced")
+	def.table.Checks = append(def.table.Checks, Check{Name: name, Clause: "(" + clause + ")", Enforced: enforced})
+	return nil
+}
+
+// typeAliases maps the data type synonyms to the names used in INFORMATION_SCHEMA
+var typeAliases = map[string]string{
+	"integer":   "int",
+	"int1":      "tinyint",
+	"int2":      "smallint",
+	"int3":      "mediumint",
+	"middleint": "mediumint",
+	"int4":      "int",
+	"int8":      "bigint",
+	"bool":      "tinyint",
+	"boolean":   "tinyint",
+	"dec":       "decimal",
+	"numeric":   "decimal",
+	"fixed":     "decimal",
+	"real":      "double",
+	"float4":    "float",
+	"float8":    "double",
+	"character": "char",
+	"nchar":     "char",
+	"nvarchar":  "varchar",
+	"serial":    "bigint",
+}
+
+// Sizes in bytes of the text and blob types
+var lobSizes = map[string]int64{
+	"tinytext": 255, "text": 65535, "mediumtext": 16777215, "longtext": 4294967295,
+	"tinyblob": 255, "blob": 65535, "mediumblob": 16777215, "longblob": 4294967295,
+}
+
+// Precision of the integer types
+var intPrecisions = map[string]int64{
+	"tinyint": 3, "smallint": 5, "mediumint": 7, "int": 10, "bigint": 19,
+}
+
+// charsetMaxLen is the maximum number of bytes per char of the most common character sets
+var charsetMaxLen = map[string]int64{
+	"utf8mb4": 4, "utf8mb3": 3, "utf8": 3, "utf16": 4, "utf16le": 4, "utf32": 4, "ucs2": 2,
+	"gbk": 2, "gb2312": 2, "gb18030": 4, "big5": 2, "sjis": 2, "cp932": 2, "ujis": 3, "eucjpms": 3, "euckr": 2,
+}
+
+func (p *ddlParser) parseColumn(def *tableDef) error {
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	typeTok := p.next()
+	if typeTok.kind != tokWord {
+		return fmt.Errorf("expected the data type of column %s near %q", name, p.stmt[typeTok.start:])
+	}
+
+	f := Field{
+		TableCatalog:    "def",
+		TableSchema:     def.table.Schema,
+		TableName:       def.table.Name,
+		ColumnName:      name,
+		OrdinalPosition: len(def.table.Fields) + 1,
+		IsNullable:      true,
+		DataType:        typeTok.text,
+	}
+	fd := &fieldDef{}
+	switch {
+	case f.DataType == "double" && p.accept(tokWord, "precision"):
+	case f.DataType == "character" && p.accept(tokWord, "varying"):
+		f.DataType = "varchar"
+	case f.DataType == "national":
+		f.DataType = p.next().text
+		if f.DataType == "character" && p.accept(tokWord, "varying") {
+			f.DataType = "varchar"
+		}
+	case f.DataType == "long" && p.accept(tokWord, "varchar"):
+		f.DataType = "mediumtext"
+	}
+	if alias, ok := typeAliases[f.DataType]; ok {
+		f.DataType = alias
+	}
+	columnType := f.DataType
+
+	var args []string
+	if p.peek().text == "(" {
+		argsText, err := p.skipParens()
+		if err != nil {
+			return err
+		}
+		columnType += "(" + argsText + ")"
+		if f.DataType != "enum" && f.DataType != "set" {
+			for _, arg := range strings.Split(argsText, ",") {
+				args = append(args, strings.TrimSpace(arg))
+			}
+		}
+	}
+	switch typeTok.text {
+	case "bool", "boolean":
+		columnType = "tinyint(1)"
+	case "serial":
+		columnType = "bigint unsigned"
+		f.IsNullable = false
+		f.Extra = "auto_increment"
+		def.indexes = append(def.indexes, Index{Name: name, Fields: []string{name}, Unique: true, Visible: true})
+	}
+	for {
+		switch {
+		case p.accept(tokWord, "unsigned"):
+			columnType += " unsigned"
+		case p.accept(tokWord, "signed"):
+		case p.accept(tokWord, "zerofill"):
+			if !strings.Contains(columnType, "unsigned") {
+				columnType += " unsigned"
+			}
+			columnType += " zerofill"
+		default:
+			f.ColumnType = columnType
+			setTypeAttributes(&f, args)
+			if err := p.parseColumnAttributes(def, &f, fd); err != nil {
+				return fmt.Errorf("column %s: %s", name, err)
+			}
+			def.table.Fields = append(def.table.Fields, f)
+			def.fields[name] = fd
+			return nil
+		}
+	}
+}
+
+// setTypeAttributes sets the lengths and precisions of the field from its data type and its arguments,
+// like in decimal(10,2)
+func setTypeAttributes(f *Field, args []string) {
+	arg := func(i int, def int64) int64 {
+		if i < len(args) {
+			if v, err := strconv.ParseInt(args[i], 10, 64); err == nil {
+				return v
+			}
+		}
+		return def
+	}
+	valid := func(v int64) sql.NullInt64 {
+		return sql.NullInt64{Int64: v, Valid: true}
+	}
+
+	switch f.DataType {
+	case "tinyint", "smallint", "mediumint", "int", "bigint":
+		precision := intPrecisions[f.DataType]
+		if f.DataType == "bigint" && strings.Contains(f.ColumnType, "unsigned") {
+			precision = 20
+		}
+		f.NumericPrecision, f.NumericScale = valid(precision), valid(0)
+	case "decimal":
+		f.NumericPrecision, f.NumericScale = valid(arg(0, 10)), valid(arg(1, 0))
+	case "float", "double":
+		// float(p) is a float or a double depending on the precision
+		if f.DataType == "float" && len(args) == 1 && arg(0, 0) > 24 {
+			f.DataType, f.ColumnType = "double", strings.Replace(f.ColumnType, "float("+args[0]+")", "double", 1)
+		}
+		f.NumericPrecision = valid(12)
+		if f.DataType == "double" {
+			f.NumericPrecision = valid(22)
+		}
+		if len(args) == 2 {
+			f.NumericPrecision, f.NumericScale = valid(arg(0, 0)), valid(arg(1, 0))
+		}
+	case "bit":
+		f.NumericPrecision = valid(arg(0, 1))
+	case "char", "binary":
+		f.CharacterMaximumLength = valid(arg(0, 1))
+	case "varchar", "varbinary":
+		f.CharacterMaximumLength = valid(arg(0, 0))
+	case "tinytext", "text", "mediumtext", "longtext", "tinyblob", "blob", "mediumblob", "longblob":
+		// text(n) and blob(n) are the smallest type able to hold n bytes
+		if n := arg(0, 0); n > 0 && (f.DataType == "text" || f.DataType == "blob") {
+			for _, size := range []string{"tiny", "", "medium", "long"} {
+				if n <= lobSizes[size+f.DataType] {
+					f.DataType = size + f.DataType
+					f.ColumnType = f.DataType
+					break
+				}
+			}
+		}
+		f.CharacterMaximumLength = valid(lobSizes[f.DataType])
+	case "datetime", "timestamp", "time":
+		f.DatetimePrecision = valid(arg(0, 0))
+	}
+}
+
+func (p *ddlParser) parseColumnAttributes(def *tableDef, f *Field, fd *fieldDef) error {
+	extra := []string{}
+	if f.Extra != "" {
+		extra = append(extra, f.Extra)
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokWord {
+			break
+		}
+		switch {
+		case p.acceptWords("not", "null"):
+			f.IsNullable = false
+		case p.accept(tokWord, "null"):
+		case p.accept(tokWord, "charset"), p.acceptWords("character", "set"):
+			fd.charset = p.next().text
+		case p.accept(tokWord, "collate"):
+			fd.collation = p.next().text
+		case p.accept(tokWord, "default"):
+			value, expression, err := p.parseDefault()
+			if err != nil {
+				return err
+			}
+			f.ColumnDefault = value
+			if expression {
+				extra = append(extra, "DEFAULT_GENERATED")
+			}
+		case p.acceptWords("on", "update"):
+			p.next()
+			if p.peek().text == "(" {
+				if _, err := p.skipParens(); err != nil {
+					return err
+				}
+			}
+			extra = append(extra, "on update CURRENT_TIMESTAMP")
+		case p.accept(tokWord, "auto_increment"):
+			extra = append(extra, "auto_increment")
+		case p.acceptWords("primary", "key"):
+			f.IsNullable = false
+			def.indexes = append(def.indexes, Index{Name: "PRIMARY", Fields: []string{f.ColumnName}, Unique: true,
+				Visible: true})
+		case p.accept(tokWord, "unique"):
+			p.accept(tokWord, "key")
+			def.indexes = append(def.indexes, Index{Fields: []string{f.ColumnName}, Unique: true, Visible: true})
+		case p.accept(tokWord, "key"):
+			f.IsNullable = false
+			def.indexes = append(def.indexes, Index{Name: "PRIMARY", Fields: []string{f.ColumnName}, Unique: true,
+				Visible: true})
+		case p.accept(tokWord, "comment"):
+			f.ColumnComment = p.next().text
+		case p.acceptWords("generated", "always"), p.peek().text == "as":
+			if err := p.expect(tokWord, "as"); err != nil {
+				return err
+			}
+			expr, err := p.skipParens()
+			if err != nil {
+				return err
+			}
+			f.GenerationExpression = expr
+			kind := "VIRTUAL GENERATED"
+			if p.accept(tokWord, "stored") || p.accept(tokWord, "persistent") {
+				kind = "STORED GENERATED"
+			}
+			p.accept(tokWord, "virtual")
+			extra = append(extra, kind)
+		case p.accept(tokWord, "invisible"):
+			extra = append(extra, "INVISIBLE")
+		case p.accept(tokWord, "visible"):
+		case p.accept(tokWord, "srid"):
+			f.SrsID = sql.NullString{String: p.next().text, Valid: true}
+		case p.accept(tokWord, "constraint"):
+			name, err := p.name()
+			if err != nil {
+				return err
+			}
+			if err := p.expect(tokWord, "check"); err != nil {
+				return err
+			}
+			if err := p.parseCheck(def, name); err != nil {
+				return err
+			}
+		case p.accept(tokWord, "check"):
+			if err := p.parseCheck(def, ""); err != nil {
+				return err
+			}
+		case p.accept(tokWord, "references"):
+			// Inline references are ignored by MySQL
+			if _, _, err := p.qualifiedName(); err != nil {
+				return err
+			}
+			if _, err := p.nameList(); err != nil {
+				return err
+			}
+		case p.accept(tokWord, "column_format"), p.accept(tokWord, "storage"), p.accept(tokWord, "engine_attribute"),
+			p.accept(tokWord, "secondary_engine_attribute"):
+			p.accept(tokSymbol, "=")
+			p.next()
+		default:
+			// ON DELETE, MATCH and other clauses of inline references
+			p.next()
+		}
+	}
+	f.Extra = strings.Join(extra, " ")
+	return nil
+}
+
+// parseDefault parses a default value. It returns true if the default is an expression, like
+// CURRENT_TIMESTAMP or (UUID()), instead of a literal value
+func (p *ddlParser) parseDefault() (sql.NullString, bool, error) {
+	tok := p.peek()
+	switch {
+	case tok.kind == tokString || tok.kind == tokNumber:
+		p.next()
+		return sql.NullString{String: tok.text, Valid: true}, false, nil
+	case tok.kind == tokSymbol && (tok.text == "-" || tok.text == "+"):
+		p.next()
+		v := p.next().text
+		if tok.text == "-" {
+			v = "-" + v
+		}
+		return sql.NullString{String: v, Valid: true}, false, nil
+	case tok.kind == tokSymbol && tok.text == "(":
+		expr, err := p.skipParens()
+		return sql.NullString{String: expr, Valid: true}, true, err
+	case tok.kind == tokWord && tok.text == "null":
+		p.next()
+		return sql.NullString{}, false, nil
+	case tok.kind == tokWord && (tok.text == "true" || tok.text == "false"):
+		p.next()
+		v := "0"
+		if tok.text == "true" {
+			v = "1"
+		}
+		return sql.NullString{String: v, Valid: true}, false, nil
+	case tok.kind == tokWord:
+		// CURRENT_TIMESTAMP, NOW(), LOCALTIME, etc
+		p.next()
+		if p.peek().text == "(" {
+			if _, err := p.skipParens(); err != nil {
+				return sql.NullString{}, false, err
+			}
+		}
+		return sql.NullString{String: "CURRENT_TIMESTAMP", Valid: true}, true, nil
+	}
+	return sql.NullString{}, false, fmt.Errorf("invalid default value near %q", p.context())
+}
+
+// finish sets the character sets, the indexes and the keys of the fields
+func (d *tableDef) finish() {
+	t := d.table
+	tableCharset := d.charset
+	if tableCharset == "" {
+		tableCharset = "utf8mb4"
+	}
+
+	counts := make(map[string]int)
+	for _, index := range d.indexes {
+		if index.Name == "" && len(index.Fields) > 0 {
+			index.Name = index.Fields[0]
+		}
+		if counts[index.Name]++; counts[index.Name] > 1 && index.Name != "PRIMARY" {
+			index.Name = fmt.Sprintf("%s_%d", index.Name, counts[index.Name])
+		}
+		if existing, ok := t.Indexes[index.Name]; ok && index.Name == "PRIMARY" {
+			index.Fields = append(existing.Fields, index.Fields...)
+		}
+		t.Indexes[index.Name] = index
+	}
+	// Like InnoDB, foreign keys get an index if their fields are not the first fields of another index
+	for _, index := range d.fkIndexes {
+		if _, ok := t.Indexes[index.Name]; !ok && !hasIndexPrefix(t.Indexes, index.Fields) {
+			t.Indexes[index.Name] = index
+		}
+	}
+
+	constraints := constraintsAsMap(t.Constraints)
+	for i := range t.Fields {
+		f := &t.Fields[i]
+		fd := d.fields[f.ColumnName]
+		f.Constraint = constraints[f.ColumnName]
+		f.SetEnumVals = []string{}
+		if f.DataType == "enum" || f.DataType == "set" {
+			f.SetEnumVals = parseEnumValues(f.ColumnType)
+		}
+
+		switch f.DataType {
+		case "char", "varchar", "tinytext", "text", "mediumtext", "longtext", "enum", "set":
+			charset := fd.charset
+			if charset == "" {
+				charset = tableCharset
+			}
+			if charset == "binary" {
+				break
+			}
+			maxLen, ok := charsetMaxLen[charset]
+			if !ok {
+				maxLen = 1
+			}
+			f.CharacterSetName = sql.NullString{String: charset, Valid: true}
+			if fd.collation != "" {
+				f.CollationName = sql.NullString{String: fd.collation, Valid: true}
+			}
+			if _, isLob := lobSizes[f.DataType]; isLob {
+				f.CharacterOctetLength = f.CharacterMaximumLength
+				f.CharacterMaximumLength.Int64 /= maxLen
+			} else {
+				f.CharacterOctetLength = sql.NullInt64{Int64: f.CharacterMaximumLength.Int64 * maxLen, Valid: true}
+			}
+		case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+			f.CharacterOctetLength = f.CharacterMaximumLength
+		}
+		f.ColumnKey = columnKey(t.Indexes, f.ColumnName)
+		if f.ColumnKey == "PRI" {
+			f.IsNullable = false
+		}
+	}
+}
+
+// hasIndexPrefix returns true if an index has the fields as its first fields
+func hasIndexPrefix(indexes map[string]Index, fields []string) bool {
+	for _, index := range indexes {
+		if len(index.Fields) < len(fields) {
+			continue
+		}
+		prefix := true
+		for i, f := range fields {
+			prefix = prefix && strings.EqualFold(index.Fields[i], f)
+		}
+		if prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// columnKey returns the COLUMN_KEY value for a field: PRI for primary key fields, UNI for fields having a
+// single field unique index and MUL for the first field of the other indexes
+func columnKey(indexes map[string]Index, column string) string {
+	key := ""
+	for _, index := range indexes {
+		if len(index.Fields) == 0 || index.Fields[0] != column && index.Name != "PRIMARY" {
+			continue
+		}
+		switch {
+		case index.Name == "PRIMARY":
+			for _, f := range index.Fields {
+				if f == column {
+					return "PRI"
+				}
+			}
+		case index.Unique && len(index.Fields) == 1:
+			key = "UNI"
+		case key == "":
+			key = "MUL"
+		}
+	}
+	return key
+}
+
+// parseCreateTrigger parses a CREATE TRIGGER statement after the TRIGGER keyword
+func (p *ddlParser) parseCreateTrigger() (Trigger, bool) {
+	p.acceptWords("if", "not", "exists")
+	_, name, err := p.qualifiedName()
+	if err != nil {
+		return Trigger{}, false
+	}
+	timing, event := p.next(), p.next()
+	if err := p.expect(tokWord, "on"); err != nil {
+		return Trigger{}, false
+	}
+	_, table, err := p.qualifiedName()
+	if err != nil || !p.acceptWords("for", "each", "row") {
+		return Trigger{}, false
+	}
+	// FOLLOWS/PRECEDES other_trigger
+	if p.accept(tokWord, "follows") || p.accept(tokWord, "precedes") {
+		p.next()
+	}
+	return Trigger{
+		Trigger:   name,
+		Event:     strings.ToUpper(event.text),
+		Table:     table,
+		Statement: strings.TrimSpace(p.stmt[p.peek().start:]),
+		Timing:    strings.ToUpper(timing.text),
+	}, true
+}
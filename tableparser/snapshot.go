@@ -0,0 +1,61 @@
+package tableparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// WriteDefinitions writes the tables definitions to w as a JSON array. The output can be read back with
+// ReadDefinitions to get the same tables without a connection to the server.
+func WriteDefinitions(w io.Writer, tables []*Table) error {
+	buf, err := json.MarshalIndent(tables, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(buf, '\n'))
+	return err
+}
+
+// ReadDefinitions reads the tables definitions written by WriteDefinitions. A single table definition,
+// not enclosed in an array, is also accepted.
+func ReadDefinitions(r io.Reader) ([]*Table, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := []*Table{}
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		table := &Table{}
+		if err := json.Unmarshal(data, table); err != nil {
+			return nil, fmt.Errorf("invalid table definition: %s", err)
+		}
+		tables = append(tables, table)
+	} else if err := json.Unmarshal(data, &tables); err != nil {
+		return nil, fmt.Errorf("invalid tables definitions: %s", err)
+	}
+
+	for _, table := range tables {
+		if table == nil || table.Name == "" || len(table.Fields) == 0 {
+			return nil, fmt.Errorf("invalid table definition: the table name and fields are required")
+		}
+		if table.Indexes == nil {
+			table.Indexes = make(map[string]Index)
+		}
+		constraints := constraintsAsMap(table.Constraints)
+		for i := range table.Fields {
+			f := &table.Fields[i]
+			if f.SetEnumVals == nil {
+				f.SetEnumVals = parseEnumValues(f.ColumnType)
+			}
+			if c, ok := constraints[f.ColumnName]; ok {
+				f.Constraint = c
+			}
+		}
+	}
+	return tables, nil
+}
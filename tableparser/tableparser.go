@@ -5,7 +5,6 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"net/url"
-	"regexp"
 	"strings"
 	"time"
 
@@ -40,6 +39,7 @@ type Table struct {
 	Indexes map[string]Index
 	//TODO Include complete indexes information
 	Constraints []Constraint
+	Checks      []Check // MySQL 8.0.16+
 	Triggers    []Trigger
 	//
 	conn *sql.DB
@@ -52,6 +52,8 @@ type Index struct {
 	Unique     bool
 	Visible    bool
 	Expression string // MySQL 8.0.16+
+	// SubParts holds the prefix lengths of the fields indexed by a prefix, like name in KEY (name(10))
+	SubParts map[string]int64 `json:",omitempty"`
 }
 
 // IndexField holds raw index information as defined in INFORMATION_SCHEMA table
@@ -81,6 +83,13 @@ type Constraint struct {
 	ReferencedColumnName  string
 }
 
+// Check holds a CHECK constraint definition as defined in INFORMATION_SCHEMA
+type Check struct {
+	Name     string
+	Clause   string
+	Enforced bool
+}
+
 // Field holds raw field information as defined in INFORMATION_SCHEMA
 type Field struct {
 	TableCatalog           string
@@ -109,6 +118,11 @@ type Field struct {
 	SrsID                  sql.NullString
 }
 
+// IsUnsigned returns true if the field is an unsigned numeric field
+func (f Field) IsUnsigned() bool {
+	return strings.Contains(strings.ToLower(f.ColumnType), "unsigned")
+}
+
 // Trigger holds raw trigger information as defined in INFORMATION_SCHEMA
 type Trigger struct {
 	Trigger             string
@@ -140,6 +154,10 @@ func NewTable(db *sql.DB, schema, tableName string) (*Table, error) {
 	if err != nil {
 		return nil, err
 	}
+	table.Checks, err = getChecks(db, table.Schema, table.Name)
+	if err != nil {
+		return nil, err
+	}
 	table.Triggers, err = getTriggers(db, table.Schema, table.Name)
 	if err != nil {
 		return nil, err
@@ -154,12 +172,6 @@ func NewTable(db *sql.DB, schema, tableName string) (*Table, error) {
 }
 
 func (t *Table) parse() error {
-	//                           +--------------------------- field type
-	//                           |          +---------------- field size / enum values: decimal(10,2) or enum('a','b')
-	//                           |          |     +---------- extra info (unsigned, etc)
-	//                           |          |     |
-	re := regexp.MustCompile(`^(.*?)(?:\((.*?)\)(.*))?$`)
-
 	query := "SELECT * FROM `information_schema`.`COLUMNS` WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION"
 
 	constraints := constraintsAsMap(t.Constraints)
@@ -187,16 +199,7 @@ func (t *Table) parse() error {
 
 		allowedValues := []string{}
 		if f.DataType == "enum" || f.DataType == "set" {
-			m := re.FindStringSubmatch(f.ColumnType)
-			if len(m) < 2 {
-				continue
-			}
-			vals := strings.Split(m[2], ",")
-			for _, val := range vals {
-				val = strings.TrimPrefix(val, "'")
-				val = strings.TrimSuffix(val, "'")
-				allowedValues = append(allowedValues, val)
-			}
+			allowedValues = parseEnumValues(f.ColumnType)
 		}
 
 		f.SetEnumVals = allowedValues
@@ -212,6 +215,47 @@ func (t *Table) parse() error {
 	return nil
 }
 
+// parseEnumValues returns the list of values in an enum or set column type, like enum('a','b').
+// Values are quoted and can have commas, parentheses and escaped quotes (doubled or backslash escaped) so the list
+// cannot be just split on commas.
+func parseEnumValues(columnType string) []string {
+	values := []string{}
+	start := strings.Index(columnType, "(")
+	if start < 0 {
+		return values
+	}
+
+	var val strings.Builder
+	inQuotes := false
+	for i := start + 1; i < len(columnType); i++ {
+		c := columnType[i]
+		if !inQuotes {
+			if c == ')' {
+				break
+			}
+			if c == '\'' {
+				inQuotes = true
+				val.Reset()
+			}
+			continue
+		}
+		switch {
+		case c == '\\' && i+1 < len(columnType):
+			i++
+			val.WriteByte(columnType[i])
+		case c == '\'' && i+1 < len(columnType) && columnType[i+1] == '\'':
+			i++
+			val.WriteByte(c)
+		case c == '\'':
+			inQuotes = false
+			values = append(values, val.String())
+		default:
+			val.WriteByte(c)
+		}
+	}
+	return values
+}
+
 func makeScanRecipients(f *Field, allowNull *string, cols []string) []interface{} {
 	fields := []interface{}{
 		&f.TableCatalog,
@@ -253,6 +297,15 @@ func (t *Table) FieldNames() []string {
 	return fields
 }
 
+// Backticks quotes an identifier with backticks, doubling the backticks it has. Identifiers already
+// quoted are returned unchanged
+func Backticks(val string) string {
+	if len(val) > 1 && strings.HasPrefix(val, "`") && strings.HasSuffix(val, "`") {
+		return val
+	}
+	return "`" + strings.Replace(val, "`", "``", -1) + "`"
+}
+
 func getIndexes(db *sql.DB, schema, tableName string) (map[string]Index, error) {
 	query := fmt.Sprintf("SHOW INDEXES FROM `%s`.`%s`", schema, tableName)
 	rows, err := db.Query(query)
@@ -282,19 +335,26 @@ func getIndexes(db *sql.DB, schema, tableName string) (map[string]Index, error)
 		if err != nil {
 			return nil, fmt.Errorf("cannot read indexes: %s", err)
 		}
-		if index, ok := indexes[i.KeyName]; !ok {
-			indexes[i.KeyName] = Index{
+		index, ok := indexes[i.KeyName]
+		if !ok {
+			index = Index{
 				Name:       i.KeyName,
 				Unique:     !i.NonUnique,
 				Fields:     []string{i.ColumnName},
 				Visible:    i.Visible == "YES" || visible == "",
 				Expression: i.Expression.String,
 			}
-
 		} else {
 			index.Fields = append(index.Fields, i.ColumnName)
 			index.Unique = index.Unique || !i.NonUnique
 		}
+		if i.SubPart.Valid {
+			if index.SubParts == nil {
+				index.SubParts = make(map[string]int64)
+			}
+			index.SubParts[i.ColumnName] = i.SubPart.Int64
+		}
+		indexes[i.KeyName] = index
 	}
 	if err := rows.Close(); err != nil {
 		return nil, errors.Wrap(err, "Cannot close query rows at getIndexes")
@@ -336,6 +396,42 @@ func getConstraints(db *sql.DB, schema, tableName string) ([]Constraint, error)
 	return constraints, nil
 }
 
+// getChecks returns the CHECK constraints of the table. Servers before MySQL 8.0.16 don't have the
+// CHECK_CONSTRAINTS table and don't enforce CHECK constraints, so no constraints are returned for them.
+func getChecks(db *sql.DB, schema, tableName string) ([]Check, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM information_schema.TABLES " +
+		"WHERE TABLE_SCHEMA = 'information_schema' AND TABLE_NAME = 'CHECK_CONSTRAINTS'"
+	if err := db.QueryRow(query).Scan(&count); err != nil || count == 0 {
+		return nil, err
+	}
+
+	query = "SELECT cc.CONSTRAINT_NAME, cc.CHECK_CLAUSE, tc.ENFORCED " +
+		"FROM information_schema.TABLE_CONSTRAINTS tc " +
+		"JOIN information_schema.CHECK_CONSTRAINTS cc " +
+		"ON tc.CONSTRAINT_SCHEMA = cc.CONSTRAINT_SCHEMA AND tc.CONSTRAINT_NAME = cc.CONSTRAINT_NAME " +
+		"WHERE tc.CONSTRAINT_TYPE = 'CHECK' AND tc.TABLE_SCHEMA = ? AND tc.TABLE_NAME = ? " +
+		"ORDER BY cc.CONSTRAINT_NAME"
+	rows, err := db.Query(query, schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var checks []Check
+	for rows.Next() {
+		var c Check
+		var enforced string
+		if err := rows.Scan(&c.Name, &c.Clause, &enforced); err != nil {
+			return nil, fmt.Errorf("cannot read check constraints: %s", err)
+		}
+		c.Enforced = enforced == "YES"
+		checks = append(checks, c)
+	}
+
+	return checks, rows.Err()
+}
+
 func getTriggers(db *sql.DB, schema, tableName string) ([]Trigger, error) {
 	query := fmt.Sprintf("SHOW TRIGGERS FROM `%s` LIKE '%s'", schema, tableName)
 	rows, err := db.Query(query)
@@ -373,3 +469,113 @@ func constraintsAsMap(constraints []Constraint) map[string]*Constraint {
 	}
 	return m
 }
+
+// GetTableNames returns the names of all the base tables in a schema
+func GetTableNames(db *sql.DB, schema string) ([]string, error) {
+	query := "SELECT TABLE_NAME FROM `information_schema`.`TABLES` " +
+		"WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME"
+	rows, err := db.Query(query, schema)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := []string{}
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("cannot read table names: %s", err)
+		}
+		tables = append(tables, name)
+	}
+
+	return tables, rows.Err()
+}
+
+// DependencyCycle returns the names of the tables in a foreign keys cycle going from table back to
+// itself, like [store staff store], or nil if the table is not in a cycle. Self references are cycles
+// of one table, like [category category]. References to tables not in the list are ignored.
+func DependencyCycle(tables []*Table, table *Table) []string {
+	index := make(map[string]*Table)
+	for _, t := range tables {
+		index[t.Schema+"."+t.Name] = t
+	}
+	visited := make(map[*Table]bool)
+	var walk func(t *Table, path []string) []string
+	walk = func(t *Table, path []string) []string {
+		visited[t] = true
+		for _, c := range t.Constraints {
+			parent, ok := index[c.ReferencedTableSchema+"."+c.ReferencedTableName]
+			if !ok {
+				continue
+			}
+			if parent == table {
+				return append(path, parent.Name)
+			}
+			if visited[parent] {
+				continue
+			}
+			if cycle := walk(parent, append(path, parent.Name)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return walk(table, []string{table.Name})
+}
+
+// SortByDependencies sorts the tables in foreign keys dependency order, so referenced (parent)
+// tables come before the tables referencing them.
+// References to tables not in the list and self references are ignored.
+// Tables having circular dependencies (and the tables depending on them) cannot be sorted. They are
+// appended at the end of the list in their original order and also returned in the second value so
+// the caller can warn about them.
+func SortByDependencies(tables []*Table) ([]*Table, []*Table) {
+	index := make(map[string]int)
+	for i, t := range tables {
+		index[t.Schema+"."+t.Name] = i
+	}
+
+	// pending[i] is the number of parents of tables[i] not yet in the sorted list
+	pending := make([]int, len(tables))
+	children := make([][]int, len(tables))
+	for i, t := range tables {
+		parents := make(map[int]bool)
+		for _, c := range t.Constraints {
+			p, ok := index[c.ReferencedTableSchema+"."+c.ReferencedTableName]
+			if !ok || p == i || parents[p] {
+				continue
+			}
+			parents[p] = true
+			children[p] = append(children[p], i)
+			pending[i]++
+		}
+	}
+
+	sorted := make([]*Table, 0, len(tables))
+	done := make([]bool, len(tables))
+	for progress := true; progress; {
+		progress = false
+		for i, t := range tables {
+			if done[i] || pending[i] > 0 {
+				continue
+			}
+			sorted = append(sorted, t)
+			done[i] = true
+			progress = true
+			for _, c := range children[i] {
+				pending[c]--
+			}
+		}
+	}
+
+	cyclic := []*Table{}
+	for i, t := range tables {
+		if !done[i] {
+			cyclic = append(cyclic, t)
+		}
+	}
+
+	return append(sorted, cyclic...), cyclic
+}
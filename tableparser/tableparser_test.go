@@ -1,6 +1,10 @@
 package tableparser
 
 import (
+	"bytes"
+	"database/sql"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -79,3 +83,233 @@ func TestGetTriggers(t *testing.T) {
 	tu.Ok(t, err)
 	tu.Equals(t, triggers, want)
 }
+
+func TestSortByDependencies(t *testing.T) {
+	fk := func(table string) Constraint {
+		return Constraint{ReferencedTableSchema: "test", ReferencedTableName: table}
+	}
+	tables := []*Table{
+		{Schema: "test", Name: "rental", Constraints: []Constraint{fk("inventory"), fk("customer")}},
+		{Schema: "test", Name: "inventory", Constraints: []Constraint{fk("film")}},
+		{Schema: "test", Name: "customer", Constraints: []Constraint{fk("address")}},
+		{Schema: "test", Name: "film", Constraints: []Constraint{fk("language")}}, // language is not in the list
+		{Schema: "test", Name: "address"},
+		{Schema: "test", Name: "category", Constraints: []Constraint{fk("category")}}, // self reference
+		{Schema: "test", Name: "store", Constraints: []Constraint{fk("staff")}},
+		{Schema: "test", Name: "staff", Constraints: []Constraint{fk("store")}},
+	}
+
+	sorted, cyclic := SortByDependencies(tables)
+	names := []string{}
+	for _, table := range sorted {
+		names = append(names, table.Name)
+	}
+	tu.Equals(t, []string{"film", "address", "category", "inventory", "customer", "rental", "store", "staff"}, names)
+	tu.Equals(t, []*Table{tables[6], tables[7]}, cyclic)
+
+	tu.Equals(t, []string{"store", "staff", "store"}, DependencyCycle(tables, tables[6]))
+	tu.Equals(t, []string{"category", "category"}, DependencyCycle(tables, tables[5]))
+	tu.Assert(t, DependencyCycle(tables, tables[0]) == nil, "rental is not in a cycle")
+}
+
+func TestParseEnumValues(t *testing.T) {
+	tests := map[string][]string{
+		"enum('G','PG','PG-13')":            {"G", "PG", "PG-13"},
+		"set('a,b','c(d)','it''s','x\\'y')": {"a,b", "c(d)", "it's", "x'y"},
+		"enum('','a')":                      {"", "a"},
+		"enum('a)','b')":                    {"a)", "b"},
+		"varchar(10)":                       {},
+	}
+	for columnType, want := range tests {
+		tu.Equals(t, want, parseEnumValues(columnType))
+	}
+}
+
+func TestBackticks(t *testing.T) {
+	tests := map[string]string{
+		"film":       "`film`",
+		"my table":   "`my table`",
+		"a`b":        "`a``b`",
+		"`quoted`":   "`quoted`",
+		"`":          "````",
+		"café_ñandú": "`café_ñandú`",
+	}
+	for name, want := range tests {
+		tu.Equals(t, want, Backticks(name))
+	}
+}
+
+func TestParseCheck(t *testing.T) {
+	tests := map[string][]CheckCondition{
+		"((`price` >= 0) and (`price` <= 1000))": {
+			{Column: "price", Op: ">=", Values: []string{"0"}},
+			{Column: "price", Op: "<=", Values: []string{"1000"}},
+		},
+		"(`qty` between -(5) and 10)": {{Column: "qty", Op: "between", Values: []string{"-5", "10"}}},
+		"(`status` in (_utf8mb4'active',_utf8mb4'it''s'))": {
+			{Column: "status", Op: "in", Values: []string{"active", "it's"}},
+		},
+		"(`status` not in (_utf8mb4'x'))": {{Column: "status", Op: "not in", Values: []string{"x"}}},
+		"(char_length(`name`) >= 3)":      {{Column: "name", Function: "char_length", Op: ">=", Values: []string{"3"}}},
+		"(`end_date` > `start_date`)":     {{Column: "end_date", Op: ">", RefColumn: "start_date"}},
+		"(10 > `qty`)":                    {{Column: "qty", Op: "<", Values: []string{"10"}}},
+		"price != 0 && length(code) <= 5": {
+			{Column: "price", Op: "<>", Values: []string{"0"}},
+			{Column: "code", Function: "length", Op: "<=", Values: []string{"5"}},
+		},
+		"(`a` is not null)":                               {{Column: "a", Op: "is not null"}},
+		"(`code` in (x'0a',X'ff', b'1000001'))":           {{Column: "code", Op: "in", Values: []string{"\n", "\xff", "A"}}},
+		"((`length` > 0) or (`length` is null))":          {{Column: "length", Op: ">", Values: []string{"0"}}},
+		"`a` is null or (`a` is not null and `a` <= `b`)": {{Column: "a", Op: "<=", RefColumn: "b"}},
+	}
+	for clause, want := range tests {
+		got, err := ParseCheck(clause)
+		tu.Ok(t, err)
+		tu.Equals(t, want, got)
+	}
+
+	for _, clause := range []string{"((`a` > 0) or (`b` > 0))", "(`a` > 0) or (`b` is null)", "(`a` = x'0')", "(json_valid(`doc`))",
+		"(not (`a` > 0))", "(`a` + 1 > 0)", "(`a` not between 1 and 2)"} {
+		_, err := ParseCheck(clause)
+		tu.NotOk(t, err)
+	}
+}
+
+func TestParseDDL(t *testing.T) {
+	f, err := os.Open("testdata/sakila.sql")
+	tu.Ok(t, err)
+	defer f.Close()
+
+	tables, err := ParseDDL(f, "test")
+	tu.Ok(t, err)
+	tu.Equals(t, 2, len(tables))
+	tu.Equals(t, "sakila", tables[1].Schema)
+	tu.Equals(t, "film", tables[1].Name)
+
+	film := tables[1]
+	fields := make(map[string]Field)
+	for _, f := range film.Fields {
+		fields[f.ColumnName] = f
+	}
+	tu.Equals(t, 14, len(film.Fields))
+
+	id := fields["film_id"]
+	tu.Equals(t, "smallint unsigned", id.ColumnType)
+	tu.Equals(t, "PRI", id.ColumnKey)
+	tu.Equals(t, "auto_increment", id.Extra)
+	tu.Equals(t, false, id.IsNullable)
+	tu.Equals(t, sql.NullInt64{Int64: 5, Valid: true}, id.NumericPrecision)
+
+	title := fields["title"]
+	tu.Equals(t, "UNI", title.ColumnKey)
+	tu.Equals(t, sql.NullInt64{Int64: 128, Valid: true}, title.CharacterMaximumLength)
+	tu.Equals(t, sql.NullInt64{Int64: 512, Valid: true}, title.CharacterOctetLength)
+
+	rate := fields["rental_rate"]
+	tu.Equals(t, sql.NullString{String: "4.99", Valid: true}, rate.ColumnDefault)
+	tu.Equals(t, sql.NullInt64{Int64: 2, Valid: true}, rate.NumericScale)
+	tu.Equals(t, "Cost; in USD", fields["replacement_cost"].ColumnComment)
+	tu.Equals(t, []string{"G", "PG", "PG-13", "R", "NC-17"}, fields["rating"].SetEnumVals)
+	tu.Equals(t, true, fields["description"].IsNullable)
+
+	code := fields["code"]
+	tu.Equals(t, "VIRTUAL GENERATED", code.Extra)
+	tu.Equals(t, "upper(`title`)", code.GenerationExpression)
+	tu.Equals(t, sql.NullInt64{Int64: 10, Valid: true}, code.CharacterOctetLength)
+
+	tu.Equals(t, "DEFAULT_GENERATED on update CURRENT_TIMESTAMP", fields["last_update"].Extra)
+
+	lang := fields["language_id"]
+	tu.Equals(t, "MUL", lang.ColumnKey)
+	tu.Equals(t, &Constraint{
+		ConstraintName:        "fk_film_language",
+		ColumnName:            "language_id",
+		ReferencedTableSchema: "sakila",
+		ReferencedTableName:   "language",
+		ReferencedColumnName:  "language_id",
+	}, lang.Constraint)
+	tu.Equals(t, 2, len(film.Constraints))
+
+	tu.Equals(t, Index{Name: "PRIMARY", Fields: []string{"film_id"}, Unique: true, Visible: true},
+		film.Indexes["PRIMARY"])
+	tu.Equals(t, Index{Name: "idx_upper", Expression: "upper(`title`)"}, film.Indexes["idx_upper"])
+	tu.Equals(t, []Check{{Name: "film_chk_1", Clause: "((`rental_rate` >= 0))", Enforced: true}}, film.Checks)
+
+	tu.Equals(t, 1, len(film.Triggers))
+	tu.Equals(t, "ins_film", film.Triggers[0].Trigger)
+	tu.Equals(t, "AFTER", film.Triggers[0].Timing)
+	tu.Equals(t, "INSERT", film.Triggers[0].Event)
+}
+
+func TestParseCreateTable(t *testing.T) {
+	table, err := ParseCreateTable("test", "CREATE TABLE IF NOT EXISTS t1 (\n"+
+		"id BIGINT UNSIGNED PRIMARY KEY, uid BINARY(16) NOT NULL UNIQUE, flag BOOL DEFAULT TRUE,\n"+
+		"price FLOAT, body TEXT(1000), created DATETIME(3) DEFAULT (now(3)) INVISIBLE,\n"+
+		"qty INT CHECK (qty > 0) NOT ENFORCED, INDEX (created, qty)) DEFAULT CHARSET=latin1")
+	tu.Ok(t, err)
+	tu.Equals(t, "test", table.Schema)
+	tu.Equals(t, "t1", table.Name)
+
+	tu.Equals(t, "bigint unsigned", table.Fields[0].ColumnType)
+	tu.Equals(t, "PRI", table.Fields[0].ColumnKey)
+	tu.Equals(t, sql.NullInt64{Int64: 20, Valid: true}, table.Fields[0].NumericPrecision)
+	tu.Equals(t, "UNI", table.Fields[1].ColumnKey)
+	tu.Equals(t, sql.NullInt64{Int64: 16, Valid: true}, table.Fields[1].CharacterOctetLength)
+	tu.Equals(t, "tinyint(1)", table.Fields[2].ColumnType)
+	tu.Equals(t, sql.NullString{String: "1", Valid: true}, table.Fields[2].ColumnDefault)
+	tu.Equals(t, sql.NullInt64{Int64: 12, Valid: true}, table.Fields[3].NumericPrecision)
+	tu.Equals(t, false, table.Fields[3].NumericScale.Valid)
+	tu.Equals(t, "text", table.Fields[4].DataType)
+	tu.Equals(t, sql.NullInt64{Int64: 65535, Valid: true}, table.Fields[4].CharacterOctetLength)
+	tu.Equals(t, "DEFAULT_GENERATED INVISIBLE", table.Fields[5].Extra)
+	tu.Equals(t, sql.NullInt64{Int64: 3, Valid: true}, table.Fields[5].DatetimePrecision)
+	tu.Equals(t, "MUL", table.Fields[5].ColumnKey)
+	tu.Equals(t, []Check{{Name: "t1_chk_1", Clause: "(qty > 0)", Enforced: false}}, table.Checks)
+
+	// Foreign keys without an index get one, like in InnoDB
+	table, err = ParseCreateTable("test", "CREATE TABLE t4 (id INT, a INT, b INT, c BIT(9) DEFAULT b'100000001', "+
+		"d VARBINARY(2) DEFAULT x'0F1e', KEY idx_b (b, a), UNIQUE KEY uk_d (d(1), a), "+
+		"FOREIGN KEY (a) REFERENCES t1 (id), CONSTRAINT fk_b FOREIGN KEY (b) REFERENCES t1 (id))")
+	tu.Ok(t, err)
+	tu.Equals(t, "MUL", table.Fields[1].ColumnKey)
+	tu.Equals(t, Index{Name: "t4_ibfk_1", Fields: []string{"a"}, Visible: true}, table.Indexes["t4_ibfk_1"])
+	_, ok := table.Indexes["fk_b"]
+	tu.Equals(t, false, ok)
+	tu.Equals(t, map[string]int64{"d": 1}, table.Indexes["uk_d"].SubParts)
+	tu.Equals(t, "\x01\x01", table.Fields[3].ColumnDefault.String)
+	tu.Equals(t, "\x0f\x1e", table.Fields[4].ColumnDefault.String)
+
+	_, err = ParseCreateTable("test", "CREATE TABLE t2 LIKE t1")
+	tu.NotOk(t, err)
+	_, err = ParseCreateTable("test", "CREATE TABLE t3 (id INT")
+	tu.NotOk(t, err)
+}
+
+func TestReadWriteDefinitions(t *testing.T) {
+	var want *Table
+	tu.LoadJson(t, "table003.json", &want)
+
+	f, err := os.Open("testdata/table003.json")
+	tu.Ok(t, err)
+	defer f.Close()
+	tables, err := ReadDefinitions(f)
+	tu.Ok(t, err)
+	tu.Equals(t, []*Table{want}, tables)
+
+	ddl, err := os.Open("testdata/sakila.sql")
+	tu.Ok(t, err)
+	defer ddl.Close()
+	tables, err = ParseDDL(ddl, "sakila")
+	tu.Ok(t, err)
+
+	var buf bytes.Buffer
+	tu.Ok(t, WriteDefinitions(&buf, tables))
+	got, err := ReadDefinitions(&buf)
+	tu.Ok(t, err)
+	tu.Equals(t, tables, got)
+
+	for _, data := range []string{"", "[{}]", `{"Name": "t1"}`, "[1]"} {
+		_, err := ReadDefinitions(strings.NewReader(data))
+		tu.NotOk(t, err)
+	}
+}